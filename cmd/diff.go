@@ -0,0 +1,109 @@
+/*
+Copyright © 2025 Dmitry Mozzherin <dmozzherin@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/gnames/gn"
+	"github.com/sfborg/harvester/internal/output"
+	"github.com/sfborg/harvester/internal/sysio"
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/sfborg/harvester/pkg/diff"
+	"github.com/spf13/cobra"
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff <old-sfga-file> <new-sfga-file> [flags]",
+	Short: "Compares two versions of the same data source in SFGA format.",
+	Long: `Compares two versions of the same data source in SFGA format.
+
+Name usages are matched by their IDs, usages without a match are matched by
+their scientific names. The command reports added and removed usages, changes
+between accepted and synonym status, new parents, ranks and authorships, and
+added or removed vernacular names and distributions.
+
+A summary is printed to the screen, all changes are saved to a detail file.
+If the file has a ".json" extension, changes are saved as JSON, otherwise
+as TSV.`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			cmd.Help()
+			return nil
+		}
+
+		cfg := config.New(opts...)
+		workDir := cfg.WorkDir("diff")
+		defer os.RemoveAll(workDir)
+
+		ctx, stop := interruptContext()
+		defer stop()
+
+		slog.Info("comparing archives", "old", args[0], "new", args[1])
+		gn.Message("Comparing <em>%s</em> to <em>%s</em>", args[0], args[1])
+		oldArc, err := sysio.OpenSfga(args[0], filepath.Join(workDir, "old"))
+		if err != nil {
+			gn.PrintErrorMessage(err)
+			return err
+		}
+		defer oldArc.Close()
+
+		newArc, err := sysio.OpenSfga(args[1], filepath.Join(workDir, "new"))
+		if err != nil {
+			gn.PrintErrorMessage(err)
+			return err
+		}
+		defer newArc.Close()
+
+		res, err := diff.Compare(ctx, oldArc.Db(), newArc.Db())
+		if err != nil {
+			gn.PrintErrorMessage(err)
+			return err
+		}
+
+		outPath, _ := cmd.Flags().GetString("output")
+		if err = res.Write(outPath); err != nil {
+			gn.PrintErrorMessage(err)
+			return err
+		}
+
+		fmt.Println()
+		output.DiffTable(res)
+		gn.Info("Saved %d changes to <em>%s</em>", res.Total(), outPath)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().StringP(
+		"output", "o", "sfga-diff.tsv",
+		"path of the detail file, use .json extension for JSON",
+	)
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"log/slog"
+	"regexp"
 
 	"github.com/gnames/gn"
 	"github.com/gnames/gnfmt"
@@ -14,6 +15,8 @@ import (
 
 type flagFunc func(cmd *cobra.Command)
 
+var labelFileRe = regexp.MustCompile(`^([a-z][a-z0-9_-]*)=(.+)$`)
+
 func versionFlag(cmd *cobra.Command) bool {
 	b, _ := cmd.Flags().GetBool("version")
 	if b {
@@ -37,10 +40,62 @@ func zipFlag(cmd *cobra.Command) {
 	}
 }
 
+func validateFlag(cmd *cobra.Command) {
+	b, _ := cmd.Flags().GetBool("validate")
+	if b {
+		opts = append(opts, config.OptWithValidation(true))
+	}
+}
+
+// fileFlag accepts either a single path, or several <label>=<path>
+// values when more than one source is harvested.
 func fileFlag(cmd *cobra.Command) {
-	s, _ := cmd.Flags().GetString("file")
-	if s != "" {
-		opts = append(opts, config.OptLocalFile(s))
+	ss, _ := cmd.Flags().GetStringArray("file")
+	files := make(map[string]string)
+	for _, s := range ss {
+		if m := labelFileRe.FindStringSubmatch(s); m != nil {
+			files[m[1]] = m[2]
+			continue
+		}
+		if s != "" {
+			opts = append(opts, config.OptLocalFile(s))
+		}
+	}
+	if len(files) > 0 {
+		opts = append(opts, config.OptLoadFiles(files))
+	}
+}
+
+// mirrorFlag accepts a mirror for all sources, or <label>=<mirror> values
+// for separate sources.
+func mirrorFlag(cmd *cobra.Command) {
+	ss, _ := cmd.Flags().GetStringArray("mirror")
+	mirrors := make(map[string]string)
+	for _, s := range ss {
+		if m := labelFileRe.FindStringSubmatch(s); m != nil {
+			mirrors[m[1]] = m[2]
+			continue
+		}
+		if s != "" {
+			mirrors[""] = s
+		}
+	}
+	if len(mirrors) > 0 {
+		opts = append(opts, config.OptMirrors(mirrors))
+	}
+}
+
+func jobsFlag(cmd *cobra.Command) {
+	i, _ := cmd.Flags().GetInt("jobs")
+	if i > 0 {
+		opts = append(opts, config.OptJobsNum(i))
+	}
+}
+
+func parserJobsFlag(cmd *cobra.Command) {
+	i, _ := cmd.Flags().GetInt("parser-jobs")
+	if i > 0 {
+		opts = append(opts, config.OptParserJobsNum(i))
 	}
 }
 
@@ -65,6 +120,33 @@ func skipFlag(cmd *cobra.Command) {
 	}
 }
 
+func forceFlag(cmd *cobra.Command) {
+	b, _ := cmd.Flags().GetBool("force")
+	if b {
+		opts = append(opts, config.OptWithForce(true))
+	}
+}
+
+// httpFlags set timeout, retries, proxy and rate limit of HTTP requests.
+func httpFlags(cmd *cobra.Command) {
+	d, _ := cmd.Flags().GetDuration("http-timeout")
+	if d > 0 {
+		opts = append(opts, config.OptHTTPTimeout(d))
+	}
+	if cmd.Flags().Changed("retries") {
+		i, _ := cmd.Flags().GetInt("retries")
+		opts = append(opts, config.OptHTTPRetries(i))
+	}
+	f, _ := cmd.Flags().GetFloat64("rate-limit")
+	if f > 0 {
+		opts = append(opts, config.OptHTTPRateLimit(f))
+	}
+	s, _ := cmd.Flags().GetString("proxy")
+	if s != "" {
+		opts = append(opts, config.OptHTTPProxy(s))
+	}
+}
+
 func codeFlag(cmd *cobra.Command) {
 	s, _ := cmd.Flags().GetString("code")
 	if s != "" {
@@ -73,6 +155,47 @@ func codeFlag(cmd *cobra.Command) {
 	}
 }
 
+func columnsFlag(cmd *cobra.Command) {
+	s, _ := cmd.Flags().GetString("columns")
+	if s != "" {
+		opts = append(opts, config.OptColumnsFile(s))
+	}
+}
+
+// sampleFlags limit the number of converted records for quick trials of
+// big sources.
+func sampleFlags(cmd *cobra.Command) {
+	i, _ := cmd.Flags().GetInt("limit")
+	if i > 0 {
+		opts = append(opts, config.OptLimit(i))
+	}
+	f, _ := cmd.Flags().GetFloat64("sample-rate")
+	if f > 0 && f < 1 {
+		opts = append(opts, config.OptSampleRate(f))
+	}
+}
+
+func rootFlag(cmd *cobra.Command) {
+	s, _ := cmd.Flags().GetString("root")
+	if s != "" {
+		opts = append(opts, config.OptRoot(s))
+	}
+}
+
+func inferGeneraFlag(cmd *cobra.Command) {
+	b, _ := cmd.Flags().GetBool("infer-genera")
+	if b {
+		opts = append(opts, config.OptInferGenera(true))
+	}
+}
+
+func newTaxdumpFlag(cmd *cobra.Command) {
+	b, _ := cmd.Flags().GetBool("new-taxdump")
+	if b {
+		opts = append(opts, config.OptNewTaxdump(true))
+	}
+}
+
 func badRowFlag(cmd *cobra.Command) {
 	s, _ := cmd.Flags().GetString("wrong-fields-num")
 	switch s {
@@ -22,11 +22,21 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
+	"strings"
+	"syscall"
 
 	"github.com/gnames/gn"
+	"github.com/sfborg/harvester/internal/output"
 	harvester "github.com/sfborg/harvester/pkg"
 	"github.com/sfborg/harvester/pkg/config"
 	"github.com/spf13/cobra"
@@ -34,51 +44,221 @@ import (
 
 // getCmd represents the get command
 var getCmd = &cobra.Command{
-	Use:           "get <label-or-id> [sfga-output-path] [flags]",
-	Short:         "Converts registered source to SFGA file.",
+	Use:   "get <label-or-id>... [sfga-output-path] [flags]",
+	Short: "Converts registered source to SFGA file.",
+	Long: `Converts registered source to SFGA file.
+
+If several labels are given, or the --all flag is used, sources are
+harvested in parallel and a summary is printed at the end. In this mode
+output files are saved to --output-dir and are named by the label.
+Sources that require manual steps are skipped by --all, unless their
+file is given with '--file <label>=<path>'.`,
 	SilenceErrors: true,
 	SilenceUsage:  true,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) < 1 || len(args) > 2 {
+		all, _ := cmd.Flags().GetBool("all")
+		if (!all && len(args) < 1) || (all && len(args) > 0) {
 			cmd.Help()
 			return nil
 		}
 
 		flags := []flagFunc{
 			skipFlag, fileFlag, zipFlag, delimFlag, quotesFlag, badRowFlag,
-			dateFlag, dataVersionFlag, schemaFlag,
+			dateFlag, dataVersionFlag, schemaFlag, jobsFlag, parserJobsFlag,
+			validateFlag, forceFlag, httpFlags, mirrorFlag, codeFlag,
+			columnsFlag, inferGeneraFlag, newTaxdumpFlag, sampleFlags, rootFlag,
 		}
 
 		for _, v := range flags {
 			v(cmd)
 		}
+		opts = append(opts, config.OptObserver(output.NewProgress()))
 
 		cfg := config.New(opts...)
-		hr := harvester.New(cfg)
-
-		l := getLabel(hr, args[0])
-		if l == "" {
-			err := fmt.Errorf("cannot find given source label for %s", args[0])
+		hr, err := harvester.New(cfg)
+		if err != nil {
 			gn.PrintErrorMessage(err)
-			gn.Info("use `list` command to find registered sources")
 			return err
 		}
 
-		outPath := l
-		if len(args) == 2 {
-			outPath = args[1]
-		}
+		ctx, stop := interruptContext()
+		defer stop()
 
-		err := hr.Get(l, outPath)
-		if err != nil {
-			fmt.Printf("err: %s", err)
-			gn.PrintErrorMessage(err)
-			return err
+		if !all && isSingle(hr, args) {
+			return getOne(ctx, hr, args)
 		}
-		return nil
+
+		outDir, _ := cmd.Flags().GetString("output-dir")
+		return getBatch(ctx, hr, cfg, args, all, outDir)
 	},
 }
 
+// isSingle returns true when only one source is requested, the second
+// argument, if given, is the output path.
+func isSingle(hr harvester.Harvester, args []string) bool {
+	switch len(args) {
+	case 1:
+		return true
+	case 2:
+		return getLabel(hr, args[1]) == ""
+	default:
+		return false
+	}
+}
+
+func getOne(
+	ctx context.Context,
+	hr harvester.Harvester,
+	args []string,
+) error {
+	l := getLabel(hr, args[0])
+	if l == "" {
+		err := fmt.Errorf("cannot find given source label for %s", args[0])
+		gn.PrintErrorMessage(err)
+		gn.Info("use `list` command to find registered sources")
+		return err
+	}
+
+	outPath := l
+	if len(args) == 2 {
+		outPath = args[1]
+	}
+
+	res, err := hr.Get(ctx, l, outPath)
+	if errors.Is(err, harvester.ErrUnchanged) {
+		return nil
+	}
+	if err != nil {
+		fmt.Printf("err: %s", err)
+		gn.PrintErrorMessage(err)
+		return err
+	}
+	for _, v := range res.Files {
+		gn.Info("Created <em>%s</em>", v)
+	}
+	return nil
+}
+
+func getBatch(
+	ctx context.Context,
+	hr harvester.Harvester,
+	cfg config.Config,
+	args []string,
+	all bool,
+	outDir string,
+) error {
+	if cfg.LoadFile != "" {
+		err := errors.New("ambiguous --file for several sources")
+		gn.PrintErrorMessage(err)
+		gn.Info("use `--file <label>=<path>` to set a file for a source")
+		return err
+	}
+
+	labels, err := batchLabels(hr, args, all)
+	if err != nil {
+		gn.PrintErrorMessage(err)
+		gn.Info("use `list` command to find registered sources")
+		return err
+	}
+
+	list := hr.List()
+	var tasks []harvester.Task
+	var skipped []string
+	for _, l := range labels {
+		if all && list[l].ManualSteps() && cfg.LoadFiles[l] == "" {
+			skipped = append(skipped, l)
+			continue
+		}
+		task := harvester.Task{Label: l, OutPath: filepath.Join(outDir, l)}
+		tasks = append(tasks, task)
+	}
+
+	slog.Info("batch harvest", "sources", len(tasks), "jobs", cfg.JobsNum)
+	gn.Message(
+		"Harvesting <em>%d</em> sources, <em>%d</em> at a time",
+		len(tasks), cfg.JobsNum,
+	)
+	res := hr.GetBatch(ctx, tasks)
+
+	var rows []output.SummaryRow
+	var failed int
+	for _, v := range skipped {
+		rows = append(rows, output.SummaryRow{
+			Label:  v,
+			Status: string(harvester.StatusSkipped),
+			Error:  "requires manual steps, no --file given",
+		})
+	}
+	for _, v := range res {
+		row := output.SummaryRow{
+			Label:    v.Label,
+			Status:   string(v.Status),
+			Duration: v.Duration,
+		}
+		if v.Err != nil {
+			if v.Status != harvester.StatusSkipped {
+				failed++
+			}
+			row.Error = v.Err.Error()
+		} else {
+			row.OutPath = v.OutPath
+		}
+		rows = append(rows, row)
+	}
+	slices.SortFunc(rows, func(a, b output.SummaryRow) int {
+		return strings.Compare(a.Label, b.Label)
+	})
+
+	fmt.Println()
+	output.SummaryTable(rows)
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d sources were not harvested", failed, len(res))
+	}
+	return nil
+}
+
+// batchLabels returns sorted unique labels for given arguments, or all
+// registered labels.
+func batchLabels(
+	hr harvester.Harvester,
+	args []string,
+	all bool,
+) ([]string, error) {
+	var res []string
+	if all {
+		for k := range hr.List() {
+			res = append(res, k)
+		}
+		sort.Strings(res)
+		return res, nil
+	}
+
+	for _, v := range args {
+		l := getLabel(hr, v)
+		if l == "" {
+			return nil, fmt.Errorf("cannot find given source label for %s", v)
+		}
+		res = append(res, l)
+	}
+	sort.Strings(res)
+	return slices.Compact(res), nil
+}
+
+// interruptContext returns a context that is canceled on SIGINT or SIGTERM.
+// After the first signal the default handling is restored, so a second
+// Ctrl-C terminates the program immediately.
+func interruptContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(
+		context.Background(), os.Interrupt, syscall.SIGTERM,
+	)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx, stop
+}
+
 func getLabel(hr harvester.Harvester, ds string) string {
 	var list []string
 	for k := range hr.List() {
@@ -100,20 +280,65 @@ func getLabel(hr harvester.Harvester, ds string) string {
 func init() {
 	rootCmd.AddCommand(getCmd)
 
+	getCmd.Flags().StringArrayP(
+		"file", "f", nil,
+		"get data from local file or URL, use <label>=<path> for several sources",
+	)
+	getCmd.Flags().BoolP(
+		"all", "a", false, "harvest all registered sources",
+	)
+	getCmd.Flags().IntP(
+		"jobs", "j", 0, "number of sources harvested at the same time",
+	)
+	getCmd.Flags().Int(
+		"parser-jobs", 0,
+		"number of workers that parse names of a source (default CPU number)",
+	)
 	getCmd.Flags().StringP(
-		"file", "f", "", "get data from local file or URL",
+		"output-dir", "o", ".", "directory for outputs of several sources",
 	)
 	getCmd.Flags().BoolP(
 		"skip-download", "s", false, "skip downloading and extracting source",
 	)
+	getCmd.Flags().BoolP(
+		"force", "F", false, "convert even if the input did not change",
+	)
+	getCmd.Flags().StringArray(
+		"mirror", nil,
+		"base URL or directory of a local mirror, <label>=<mirror> for a source",
+	)
+	getCmd.Flags().Duration(
+		"http-timeout", 0, "time to wait for a server response (default 5m)",
+	)
+	getCmd.Flags().Int(
+		"retries", 5, "number of retries of failed HTTP requests",
+	)
+	getCmd.Flags().Float64(
+		"rate-limit", 0, "maximum number of HTTP requests per second to a host",
+	)
+	getCmd.Flags().String(
+		"proxy", "", "URL of HTTP proxy (default from HTTP_PROXY/HTTPS_PROXY)",
+	)
 	getCmd.Flags().BoolP(
 		"zip-output", "z", false, "compress output with zip",
 	)
+	getCmd.Flags().Bool(
+		"validate", false, "check referential integrity of the created archive",
+	)
+	getCmd.Flags().Int(
+		"limit", 0, "convert only the first N records of a source",
+	)
+	getCmd.Flags().Float64(
+		"sample-rate", 0, "convert only this share of records (e.g. 0.01)",
+	)
+	getCmd.Flags().String(
+		"root", "", "keep only the subtree of a taxon given by its name or ID",
+	)
 	getCmd.Flags().BoolP(
 		"no-quotes", "Q", false,
 		"for tsv, pipe-delimited without quotes for fields",
 	)
-	rootCmd.Flags().StringP(
+	getCmd.Flags().StringP(
 		"wrong-fields-num", "w", "",
 		`how to process rows with wrong fields number
      choices: 'stop', 'ignore', 'process'
@@ -125,10 +350,25 @@ func init() {
 	getCmd.Flags().StringP(
 		"data-version", "v", "", "sets the version of the dataset",
 	)
-	rootCmd.Flags().StringP(
-		"delimiter", "d", "",
+	getCmd.Flags().StringP(
+		"delimiter", "D", "",
 		"a delimiter for delimiter-separated files like CSV/TSV/PSV etc.",
 	)
+	getCmd.Flags().StringP(
+		"code", "c", "",
+		"nomenclatural code of names in ad-hoc files (e.g. 'zoological')",
+	)
+	getCmd.Flags().String(
+		"columns", "",
+		"YAML file that maps columns of an ad-hoc file to name usage fields",
+	)
+	getCmd.Flags().Bool(
+		"infer-genera", false, "create genera for names from a list of names",
+	)
+	getCmd.Flags().Bool(
+		"new-taxdump", false,
+		"use NCBI new_taxdump with type material, hosts and lineages",
+	)
 	getCmd.Flags().StringP(
 		"schema", "S", "",
 		"path to local schema.sql file (instead of fetching from GitHub)",
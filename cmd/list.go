@@ -27,6 +27,7 @@ import (
 	"sort"
 
 	"github.com/fatih/color"
+	"github.com/gnames/gn"
 	"github.com/sfborg/harvester/internal/output"
 	harvester "github.com/sfborg/harvester/pkg"
 	"github.com/sfborg/harvester/pkg/config"
@@ -47,7 +48,11 @@ var listCmd = &cobra.Command{
 
 		cfg := config.New(opts...)
 
-		hr := harvester.New(cfg)
+		hr, err := harvester.New(cfg)
+		if err != nil {
+			gn.PrintErrorMessage(err)
+			return err
+		}
 
 		list := hr.List()
 		slog.Info("show list of data sources")
@@ -0,0 +1,120 @@
+/*
+Copyright © 2025 Dmitry Mozzherin <dmozzherin@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/gnames/gn"
+	"github.com/sfborg/harvester/internal/output"
+	"github.com/sfborg/harvester/internal/sysio"
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/sfborg/harvester/pkg/errcode"
+	"github.com/sfborg/harvester/pkg/validate"
+	"github.com/spf13/cobra"
+)
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate <sfga-file> [flags]",
+	Short: "Checks referential integrity of an SFGA file.",
+	Long: `Checks referential integrity of an SFGA file.
+
+The file can be an SQLite database, an SQL dump, or a zip file with one of
+them. Checks include dangling parent IDs, synonyms of synonyms, cycles in
+the hierarchy, records that refer to missing taxa, names or references,
+and duplicate IDs. The command exits with non-zero status if any issues are
+found.`,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			cmd.Help()
+			return nil
+		}
+
+		cfg := config.New(opts...)
+		workDir := cfg.WorkDir("validate")
+		defer os.RemoveAll(workDir)
+
+		ctx, stop := interruptContext()
+		defer stop()
+
+		slog.Info("validating archive", "file", args[0])
+		gn.Message("Validating <em>%s</em>", args[0])
+		arc, err := sysio.OpenSfga(args[0], workDir)
+		if err != nil {
+			gn.PrintErrorMessage(err)
+			return err
+		}
+		defer arc.Close()
+
+		res, err := validate.Check(ctx, arc.Db())
+		if err != nil {
+			gn.PrintErrorMessage(err)
+			return err
+		}
+
+		jsonPath, _ := cmd.Flags().GetString("json")
+		if jsonPath != "" {
+			if err = writeJSON(jsonPath, res); err != nil {
+				gn.PrintErrorMessage(err)
+				return err
+			}
+		}
+
+		if res.OK() {
+			gn.Info("No integrity issues found")
+			return nil
+		}
+
+		fmt.Println()
+		output.ValidationTable(res)
+		err = &gn.Error{
+			Code: errcode.ValidationError,
+			Msg:  "Found %d integrity issues",
+			Vars: []any{res.Total()},
+			Err:  fmt.Errorf("found %d integrity issues", res.Total()),
+		}
+		gn.PrintErrorMessage(err)
+		return err
+	},
+}
+
+func writeJSON(path string, v any) error {
+	bs, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(bs, '\n'), 0644)
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+
+	validateCmd.Flags().StringP(
+		"json", "j", "", "save the validation report as JSON to the given path",
+	)
+}
@@ -0,0 +1,348 @@
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sfborg/harvester/internal/httpclient"
+	"github.com/sfborg/harvester/pkg/event"
+)
+
+// Downloader fetches a URL into a file. Data are written to a ".part" file
+// that is renamed when the download is complete. After a network failure,
+// or in the next run after an interruption, the download continues from
+// the end of the ".part" file using an HTTP Range request.
+type Downloader struct {
+	// MaxRetries is the number of retries in a row without receiving any
+	// data, before the download gives up.
+	MaxRetries int
+
+	// Backoff is the wait before the first retry. It doubles with every
+	// following retry.
+	Backoff time.Duration
+
+	// ProgressEvery sets how often the progress of a download is reported.
+	ProgressEvery time.Duration
+
+	// Notify receives the progress of downloads and warnings about retries.
+	// If it is nil, they are only logged.
+	Notify func(event.Event)
+
+	client *http.Client
+}
+
+// NewDownloader creates a Downloader that uses the client, or the default
+// HTTP client if it is nil.
+func NewDownloader(client *http.Client) *Downloader {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Downloader{
+		MaxRetries:    5,
+		Backoff:       2 * time.Second,
+		ProgressEvery: 10 * time.Second,
+		client:        client,
+	}
+}
+
+// Result describes a finished download.
+type Result struct {
+	// NotModified is true if the server replied to a conditional request
+	// that the file did not change. Nothing was downloaded in this case.
+	NotModified bool
+
+	ETag         string
+	LastModified string
+}
+
+// partInfo keeps validators of a partial download, so it is resumed only
+// if the file on the server did not change.
+type partInfo struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+func (p partInfo) validator() string {
+	if p.ETag != "" && !strings.HasPrefix(p.ETag, "W/") {
+		return p.ETag
+	}
+	return p.LastModified
+}
+
+func (d *Downloader) notify(e event.Event) {
+	if d.Notify != nil {
+		d.Notify(e)
+	}
+}
+
+// errRetry marks failures that are worth retrying.
+type errRetry struct {
+	err  error
+	wait time.Duration
+}
+
+func (e *errRetry) Error() string { return e.err.Error() }
+func (e *errRetry) Unwrap() error { return e.err }
+
+// Download saves the URL to the path. Headers in hdr are sent only with
+// requests that start from the beginning of the file, they are used for
+// conditional requests.
+func (d *Downloader) Download(
+	ctx context.Context,
+	rawURL, path string,
+	hdr http.Header,
+) (Result, error) {
+	part := path + ".part"
+	info := readPartInfo(part, rawURL)
+
+	var lastErr error
+	for attempt := 0; attempt <= d.MaxRetries; {
+		if attempt > 0 {
+			wait := d.Backoff << (attempt - 1)
+			var re *errRetry
+			if errors.As(lastErr, &re) && re.wait > 0 {
+				wait = re.wait
+			}
+			slog.Warn("download failed, retrying",
+				"url", rawURL, "attempt", attempt, "wait", wait, "error", lastErr)
+			d.notify(event.Event{
+				Type:    event.Warning,
+				Message: fmt.Sprintf("Download failed, retrying in %s", wait),
+			})
+			if err := httpclient.Sleep(ctx, wait); err != nil {
+				return Result{}, err
+			}
+		}
+
+		offset := partSize(part)
+		res, got, err := d.attempt(ctx, rawURL, part, &info, offset, hdr)
+		if err == nil {
+			if res.NotModified {
+				return res, nil
+			}
+			os.Remove(part + ".json")
+			return res, os.Rename(part, path)
+		}
+		if ctx.Err() != nil {
+			slog.Warn("download interrupted", "url", rawURL, "kept", part)
+			return Result{}, ctx.Err()
+		}
+		var re *errRetry
+		if !errors.As(err, &re) {
+			return Result{}, err
+		}
+		lastErr = err
+		if got > 0 {
+			attempt = 1
+		} else {
+			attempt++
+		}
+	}
+	return Result{}, fmt.Errorf(
+		"download failed after %d retries: %w", d.MaxRetries, lastErr,
+	)
+}
+
+// attempt makes one request and writes the received data to the part
+// file. It returns the number of received bytes.
+func (d *Downloader) attempt(
+	ctx context.Context,
+	rawURL, part string,
+	info *partInfo,
+	offset int64,
+	hdr http.Header,
+) (Result, int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return Result{}, 0, err
+	}
+	if offset > 0 && info.validator() != "" {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		req.Header.Set("If-Range", info.validator())
+	} else {
+		offset = 0
+		for k, v := range hdr {
+			req.Header[k] = v
+		}
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return Result{}, 0, &errRetry{err: err}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && offset == 0:
+		return Result{NotModified: true}, 0, nil
+	case resp.StatusCode == http.StatusPartialContent:
+		if start, ok := rangeStart(resp); !ok || start != offset {
+			os.Remove(part)
+			err = errors.New("server returned a wrong range")
+			return Result{}, 0, &errRetry{err: err}
+		}
+	case resp.StatusCode == http.StatusOK:
+		offset = 0
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		os.Remove(part)
+		err = errors.New("cannot resume download")
+		return Result{}, 0, &errRetry{err: err}
+	case resp.StatusCode == http.StatusTooManyRequests:
+		err = errors.New("rate limited (HTTP 429)")
+		return Result{}, 0, &errRetry{err: err, wait: httpclient.RetryAfter(resp)}
+	case resp.StatusCode >= 500:
+		err = fmt.Errorf("server error: HTTP %d", resp.StatusCode)
+		return Result{}, 0, &errRetry{err: err}
+	default:
+		err = fmt.Errorf("server returned status %d", resp.StatusCode)
+		return Result{}, 0, err
+	}
+
+	res := Result{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	if offset == 0 {
+		*info = partInfo{
+			URL: rawURL, ETag: res.ETag, LastModified: res.LastModified,
+		}
+		if err = writePartInfo(part, *info); err != nil {
+			return Result{}, 0, err
+		}
+	} else {
+		res.ETag, res.LastModified = info.ETag, info.LastModified
+	}
+
+	flag := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if offset > 0 {
+		flag = os.O_WRONLY | os.O_APPEND
+	}
+	f, err := os.OpenFile(part, flag, 0644)
+	if err != nil {
+		return Result{}, 0, err
+	}
+
+	total := int64(-1)
+	if resp.ContentLength >= 0 {
+		total = offset + resp.ContentLength
+	}
+	p := newProgress(d, rawURL, offset, total)
+	n, err := io.Copy(f, io.TeeReader(resp.Body, p))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return Result{}, n, &errRetry{err: err}
+	}
+	if total >= 0 && offset+n != total {
+		err = fmt.Errorf("received %d bytes of %d", offset+n, total)
+		return Result{}, n, &errRetry{err: err}
+	}
+	p.done()
+	return res, n, nil
+}
+
+// progress reports the number of received bytes and the estimated time
+// left.
+type progress struct {
+	d        *Downloader
+	url      string
+	start    time.Time
+	last     time.Time
+	offset   int64
+	received int64
+	total    int64
+}
+
+func newProgress(d *Downloader, url string, offset, total int64) *progress {
+	now := time.Now()
+	return &progress{
+		d: d, url: url, start: now, last: now, offset: offset, total: total,
+	}
+}
+
+func (p *progress) Write(bs []byte) (int, error) {
+	p.received += int64(len(bs))
+	if p.d.ProgressEvery > 0 && time.Since(p.last) >= p.d.ProgressEvery {
+		p.last = time.Now()
+		p.report()
+	}
+	return len(bs), nil
+}
+
+func (p *progress) report() {
+	size := p.offset + p.received
+	var eta time.Duration
+	if p.total > 0 && p.received > 0 {
+		rate := float64(p.received) / time.Since(p.start).Seconds()
+		eta = time.Duration(float64(p.total-size)/rate) * time.Second
+	}
+	slog.Info("downloading", "url", p.url, "bytes", size, "total", p.total,
+		"eta", eta.Round(time.Second))
+	p.d.notify(event.Event{
+		Type:     event.Downloaded,
+		Count:    size,
+		Total:    p.total,
+		Message:  p.url,
+		Duration: eta.Round(time.Second),
+	})
+}
+
+func (p *progress) done() {
+	size := p.offset + p.received
+	slog.Info("download finished", "url", p.url, "bytes", size,
+		"duration", time.Since(p.start).Round(time.Second))
+	p.d.notify(event.Event{
+		Type: event.Downloaded, Count: size, Total: size, Message: p.url,
+	})
+}
+
+func readPartInfo(part, rawURL string) partInfo {
+	var res partInfo
+	bs, err := os.ReadFile(part + ".json")
+	if err != nil || json.Unmarshal(bs, &res) != nil || res.URL != rawURL {
+		return partInfo{}
+	}
+	return res
+}
+
+func writePartInfo(part string, info partInfo) error {
+	bs, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(part+".json", bs, 0644)
+}
+
+func partSize(part string) int64 {
+	fi, err := os.Stat(part)
+	if err != nil {
+		return 0
+	}
+	return fi.Size()
+}
+
+// rangeStart returns the first byte of a partial response.
+func rangeStart(resp *http.Response) (int64, bool) {
+	cr := resp.Header.Get("Content-Range")
+	cr, ok := strings.CutPrefix(cr, "bytes ")
+	if !ok {
+		return 0, false
+	}
+	start, _, ok := strings.Cut(cr, "-")
+	if !ok {
+		return 0, false
+	}
+	res, err := strconv.ParseInt(start, 10, 64)
+	return res, err == nil
+}
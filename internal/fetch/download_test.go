@@ -0,0 +1,140 @@
+package fetch_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sfborg/harvester/internal/fetch"
+	"github.com/sfborg/harvester/pkg/event"
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyServer serves body, breaking the connection in the middle of the
+// first full response, and failing the second request with HTTP 503.
+func flakyServer(body string) (*httptest.Server, *[]string) {
+	var ranges []string
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			ranges = append(ranges, r.Header.Get("Range"))
+			w.Header().Set("ETag", `"v1"`)
+			switch requests {
+			case 1:
+				w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+				w.Write([]byte(body[:len(body)/2]))
+				return
+			case 2:
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			var start int
+			fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-", &start)
+			if start > 0 && r.Header.Get("If-Range") == `"v1"` {
+				w.Header().Set("Content-Range",
+					fmt.Sprintf("bytes %d-%d/%d", start, len(body)-1, len(body)))
+				w.WriteHeader(http.StatusPartialContent)
+			}
+			w.Write([]byte(body[start:]))
+		},
+	))
+	return srv, &ranges
+}
+
+func testDownloader() *fetch.Downloader {
+	dl := fetch.NewDownloader(nil)
+	dl.Backoff = time.Millisecond
+	return dl
+}
+
+func TestDownloadResume(t *testing.T) {
+	assert := assert.New(t)
+	body := strings.Repeat("0123456789", 1000)
+	srv, ranges := flakyServer(body)
+	defer srv.Close()
+
+	var events []event.Event
+	dl := testDownloader()
+	dl.Notify = func(e event.Event) { events = append(events, e) }
+
+	path := filepath.Join(t.TempDir(), "data.tgz")
+	res, err := dl.Download(
+		context.Background(), srv.URL+"/data.tgz", path, nil,
+	)
+	assert.Nil(err)
+	assert.Equal(`"v1"`, res.ETag)
+	assert.Equal([]string{"", "bytes=5000-", "bytes=5000-"}, *ranges)
+
+	var warnings int
+	for _, v := range events {
+		if v.Type == event.Warning {
+			warnings++
+		}
+	}
+	assert.Equal(2, warnings)
+	last := events[len(events)-1]
+	assert.Equal(event.Downloaded, last.Type)
+	assert.Equal(int64(len(body)), last.Count)
+
+	bs, err := os.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal(body, string(bs))
+	_, err = os.Stat(path + ".part")
+	assert.True(os.IsNotExist(err))
+}
+
+func TestDownloadResumeNextRun(t *testing.T) {
+	assert := assert.New(t)
+	body := strings.Repeat("abcdefghij", 100)
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			ranges = append(ranges, r.Header.Get("Range"))
+			w.Header().Set("ETag", `"v1"`)
+			http.ServeContent(w, r, "", time.Time{}, strings.NewReader(body))
+		},
+	))
+	defer srv.Close()
+
+	url := srv.URL + "/data.zip"
+	path := filepath.Join(t.TempDir(), "data.zip")
+	err := os.WriteFile(path+".part", []byte(body[:300]), 0644)
+	assert.Nil(err)
+	info := fmt.Sprintf(`{"url":%q,"etag":"\"v1\""}`, url)
+	err = os.WriteFile(path+".part.json", []byte(info), 0644)
+	assert.Nil(err)
+
+	_, err = testDownloader().Download(context.Background(), url, path, nil)
+	assert.Nil(err)
+	assert.Equal([]string{"bytes=300-"}, ranges)
+	bs, err := os.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal(body, string(bs))
+}
+
+func TestDownloadGiveUp(t *testing.T) {
+	assert := assert.New(t)
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	))
+	defer srv.Close()
+
+	dl := testDownloader()
+	dl.MaxRetries = 2
+	path := filepath.Join(t.TempDir(), "data.zip")
+	_, err := dl.Download(context.Background(), srv.URL, path, nil)
+	assert.NotNil(err)
+	assert.Equal(3, requests)
+}
@@ -0,0 +1,178 @@
+// Package fetch downloads files of data sources into a persistent store.
+// Files are kept between harvests and are downloaded again only if they
+// changed upstream.
+package fetch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gnames/gnsys"
+)
+
+// Entry describes a downloaded file in the store.
+type Entry struct {
+	// URL of the file.
+	URL string `json:"url"`
+
+	// Path of the file in the store.
+	Path string `json:"path"`
+
+	// ETag returned by the server, if any.
+	ETag string `json:"etag,omitempty"`
+
+	// LastModified returned by the server, if any.
+	LastModified string `json:"lastModified,omitempty"`
+
+	// SHA256 is the checksum of the file.
+	SHA256 string `json:"sha256"`
+
+	// Size of the file in bytes.
+	Size int64 `json:"size"`
+
+	// FetchedAt is the time the file was downloaded.
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+// Store keeps downloaded files in a directory, one subdirectory per URL.
+type Store struct {
+	dir string
+	dl  *Downloader
+}
+
+// NewStore creates a store located in dir that uses the downloader.
+func NewStore(dir string, dl *Downloader) *Store {
+	return &Store{dir: dir, dl: dl}
+}
+
+// IsURL returns true if the path is an HTTP or HTTPS URL.
+func IsURL(path string) bool {
+	u, err := url.Parse(path)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https")
+}
+
+// Get returns the file for the URL. If the store already has a verified
+// copy of the file, the request is conditional and the copy is reused when
+// the server replies that the file did not change. The returned flag is
+// true if the file was downloaded.
+func (s *Store) Get(ctx context.Context, rawURL string) (Entry, bool, error) {
+	dir := s.entryDir(rawURL)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return Entry{}, false, err
+	}
+	cached, ok := s.cached(dir)
+
+	hdr := make(http.Header)
+	if ok {
+		if cached.ETag != "" {
+			hdr.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			hdr.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	path := filepath.Join(dir, fileName(rawURL))
+	res, err := s.dl.Download(ctx, rawURL, path, hdr)
+	if err != nil {
+		if ctx.Err() != nil {
+			return Entry{}, false, err
+		}
+		return Entry{}, false, &gnsys.ErrDownload{URL: rawURL, Err: err}
+	}
+	if res.NotModified {
+		if !ok {
+			err = errors.New("server returned 304 for an unconditional request")
+			return Entry{}, false, &gnsys.ErrDownload{URL: rawURL, Err: err}
+		}
+		slog.Info("file is not modified upstream", "url", rawURL)
+		return cached, false, nil
+	}
+
+	e := Entry{
+		URL:          rawURL,
+		Path:         path,
+		ETag:         res.ETag,
+		LastModified: res.LastModified,
+		FetchedAt:    time.Now(),
+	}
+	e.SHA256, e.Size, err = Checksum(path)
+	if err != nil {
+		return Entry{}, false, err
+	}
+	if err = writeEntry(dir, e); err != nil {
+		return Entry{}, false, err
+	}
+	return e, true, nil
+}
+
+// cached returns the entry of the directory if its file still exists and
+// matches the recorded checksum.
+func (s *Store) cached(dir string) (Entry, bool) {
+	var res Entry
+	bs, err := os.ReadFile(filepath.Join(dir, "entry.json"))
+	if err != nil {
+		return res, false
+	}
+	if err = json.Unmarshal(bs, &res); err != nil {
+		return res, false
+	}
+	sum, _, err := Checksum(res.Path)
+	if err != nil || sum != res.SHA256 {
+		slog.Warn("cached file is missing or corrupted", "path", res.Path)
+		return res, false
+	}
+	return res, true
+}
+
+func (s *Store) entryDir(rawURL string) string {
+	h := sha256.Sum256([]byte(rawURL))
+	return filepath.Join(s.dir, hex.EncodeToString(h[:8]))
+}
+
+// Checksum returns SHA-256 and the size of a file.
+func Checksum(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+func writeEntry(dir string, e Entry) error {
+	bs, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "entry.json"), bs, 0644)
+}
+
+// fileName returns the name of a downloaded file from its URL.
+func fileName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "download"
+	}
+	res := filepath.Base(u.Path)
+	if res == "." || res == "/" || res == "entry.json" {
+		return "download"
+	}
+	return res
+}
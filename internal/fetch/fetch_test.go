@@ -0,0 +1,79 @@
+package fetch_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/sfborg/harvester/internal/fetch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	assert := assert.New(t)
+	body := "version 1"
+	etag := `"v1"`
+	var requests, conditional int
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.Header.Get("If-None-Match") != "" {
+				conditional++
+			}
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag)
+			w.Write([]byte(body))
+		},
+	))
+	defer srv.Close()
+
+	ctx := context.Background()
+	url := srv.URL + "/data.tsv"
+	store := fetch.NewStore(t.TempDir(), fetch.NewDownloader(nil))
+
+	e, downloaded, err := store.Get(ctx, url)
+	assert.Nil(err)
+	assert.True(downloaded)
+	assert.Equal(int64(len(body)), e.Size)
+	assert.Equal("data.tsv", e.Path[len(e.Path)-8:])
+	sum := e.SHA256
+
+	e, downloaded, err = store.Get(ctx, url)
+	assert.Nil(err)
+	assert.False(downloaded, "not modified")
+	assert.Equal(sum, e.SHA256)
+	assert.Equal(1, conditional)
+
+	body, etag = "version 2", `"v2"`
+	e, downloaded, err = store.Get(ctx, url)
+	assert.Nil(err)
+	assert.True(downloaded, "modified")
+	assert.NotEqual(sum, e.SHA256)
+	bs, err := os.ReadFile(e.Path)
+	assert.Nil(err)
+	assert.Equal(body, string(bs))
+
+	// corrupted file is downloaded again without conditional headers
+	err = os.WriteFile(e.Path, []byte("garbage"), 0644)
+	assert.Nil(err)
+	e, downloaded, err = store.Get(ctx, url)
+	assert.Nil(err)
+	assert.True(downloaded, "corrupted")
+	assert.Equal(2, conditional)
+	assert.Equal(4, requests)
+}
+
+func TestGetError(t *testing.T) {
+	assert := assert.New(t)
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	store := fetch.NewStore(t.TempDir(), fetch.NewDownloader(nil))
+	_, _, err := store.Get(context.Background(), srv.URL+"/nope.zip")
+	assert.NotNil(err)
+}
@@ -0,0 +1,333 @@
+// Package httpclient provides the HTTP client used by all data sources.
+// Its timeouts, retries, User-Agent, proxy and rate limit are set by
+// the configuration.
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sfborg/harvester/pkg/config"
+)
+
+// defaultUserAgent is used if the configuration does not set one.
+const defaultUserAgent = "sfborg-harvester"
+
+// StatusError is returned when a server replies with an unexpected HTTP
+// status.
+type StatusError struct {
+	URL  string
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: server returned status %d", e.URL, e.Code)
+}
+
+// Client sends HTTP requests, retrying the ones that failed because of
+// network errors, rate limiting or server errors.
+type Client struct {
+	http    *http.Client
+	retries int
+	backoff time.Duration
+}
+
+// settings are parts of the configuration that change a client.
+type settings struct {
+	timeout   time.Duration
+	retries   int
+	backoff   time.Duration
+	rateLimit float64
+	proxy     string
+	userAgent string
+}
+
+var (
+	mu     sync.Mutex
+	shared = make(map[settings]*Client)
+)
+
+// Shared returns a client for the HTTP settings of the configuration.
+// Data sources with the same settings get the same client, so the rate
+// limit of a host is respected by all of them together.
+func Shared(cfg config.Config) *Client {
+	s := newSettings(cfg)
+	mu.Lock()
+	defer mu.Unlock()
+	if res, ok := shared[s]; ok {
+		return res
+	}
+	res := New(cfg)
+	shared[s] = res
+	return res
+}
+
+// New creates a client from the configuration.
+func New(cfg config.Config) *Client {
+	s := newSettings(cfg)
+	dialer := &net.Dialer{Timeout: s.timeout, KeepAlive: 30 * time.Second}
+	tr := &http.Transport{
+		Proxy:                 proxy(s.proxy),
+		DialContext:           dialer.DialContext,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       600 * time.Second,
+		TLSHandshakeTimeout:   s.timeout,
+		ResponseHeaderTimeout: s.timeout,
+	}
+	rt := &transport{
+		base:      tr,
+		userAgent: s.userAgent,
+		limiter:   newLimiter(s.rateLimit),
+	}
+	return &Client{
+		http:    &http.Client{Transport: rt},
+		retries: s.retries,
+		backoff: s.backoff,
+	}
+}
+
+func newSettings(cfg config.Config) settings {
+	res := settings{
+		timeout:   cfg.HTTPTimeout,
+		retries:   max(cfg.HTTPRetries, 0),
+		backoff:   cfg.HTTPBackoff,
+		rateLimit: cfg.HTTPRateLimit,
+		proxy:     cfg.HTTPProxy,
+		userAgent: cfg.UserAgent,
+	}
+	if res.userAgent == "" {
+		res.userAgent = defaultUserAgent
+	}
+	return res
+}
+
+// HTTP returns the underlying client. It sets User-Agent, uses the proxy
+// and respects the rate limit, but does not retry failed requests. It is
+// meant for callers that retry requests themselves.
+func (c *Client) HTTP() *http.Client {
+	return c.http
+}
+
+// Retries returns the number of retries of a failed request.
+func (c *Client) Retries() int {
+	return c.retries
+}
+
+// Backoff returns the wait before the first retry of a failed request.
+func (c *Client) Backoff() time.Duration {
+	return c.backoff
+}
+
+// Do sends a request without a body. Network errors, HTTP 429 and 5xx
+// responses are retried with an exponential backoff. Other responses are
+// returned to the caller.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	var res *http.Response
+	err := c.retry(req, func() error {
+		var err error
+		res, err = c.send(req)
+		return err
+	})
+	return res, err
+}
+
+// Fetch sends a request and returns the body of a successful response.
+// A failed reading of the body is retried like a failed request, both
+// share the same number of retries. Responses with a status other than
+// 200 return StatusError. Requests to file URLs, used by local mirrors,
+// return the content of the file.
+func (c *Client) Fetch(req *http.Request) ([]byte, error) {
+	ctx := req.Context()
+	if req.URL.Scheme == "file" {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return os.ReadFile(req.URL.Path)
+	}
+	var res []byte
+	err := c.retry(req, func() error {
+		resp, err := c.send(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return &StatusError{URL: req.URL.String(), Code: resp.StatusCode}
+		}
+		res, err = io.ReadAll(resp.Body)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return &errRetry{err: fmt.Errorf("reading response body: %w", err)}
+		}
+		return nil
+	})
+	return res, err
+}
+
+// errRetry is an error of an attempt that is worth retrying. If the
+// server asked for a wait, it is used instead of the backoff.
+type errRetry struct {
+	err  error
+	wait time.Duration
+}
+
+func (e *errRetry) Error() string { return e.err.Error() }
+func (e *errRetry) Unwrap() error { return e.err }
+
+// retry calls f until it returns an error that is not errRetry, or until
+// retries are exhausted.
+func (c *Client) retry(req *http.Request, f func() error) error {
+	var lastErr *errRetry
+	for attempt := 0; attempt <= c.retries; attempt++ {
+		if attempt > 0 {
+			wait := lastErr.wait
+			if wait == 0 {
+				wait = c.backoff << (attempt - 1)
+			}
+			slog.Warn("HTTP request failed, retrying", "url", req.URL,
+				"attempt", attempt, "wait", wait, "error", lastErr.err)
+			if err := Sleep(req.Context(), wait); err != nil {
+				return err
+			}
+		}
+
+		err := f()
+		if !errors.As(err, &lastErr) {
+			return err
+		}
+	}
+	return fmt.Errorf(
+		"request failed after %d retries: %w", c.retries, lastErr.err,
+	)
+}
+
+// send makes one attempt of a request. Network errors, HTTP 429 and 5xx
+// responses are returned as errRetry.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	resp, err := c.http.Do(req.Clone(ctx))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, &errRetry{err: err}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode >= 500 {
+		resp.Body.Close()
+		return nil, &errRetry{
+			err:  &StatusError{URL: req.URL.String(), Code: resp.StatusCode},
+			wait: RetryAfter(resp),
+		}
+	}
+	return resp, nil
+}
+
+// Get returns the body of a successful GET request to the URL.
+func (c *Client) Get(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Fetch(req)
+}
+
+// transport sets User-Agent and waits for the rate limit before sending
+// a request.
+type transport struct {
+	base      http.RoundTripper
+	userAgent string
+	limiter   *limiter
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.wait(req.Context(), req.URL.Host); err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.base.RoundTrip(req)
+}
+
+// limiter spaces requests to the same host.
+type limiter struct {
+	interval time.Duration
+	mu       sync.Mutex
+	next     map[string]time.Time
+}
+
+func newLimiter(perSecond float64) *limiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &limiter{
+		interval: time.Duration(float64(time.Second) / perSecond),
+		next:     make(map[string]time.Time),
+	}
+}
+
+func (l *limiter) wait(ctx context.Context, host string) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	t := l.next[host]
+	if t.Before(now) {
+		t = now
+	}
+	l.next[host] = t.Add(l.interval)
+	l.mu.Unlock()
+	return Sleep(ctx, time.Until(t))
+}
+
+func proxy(s string) func(*http.Request) (*url.URL, error) {
+	if s == "" {
+		return http.ProxyFromEnvironment
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return func(*http.Request) (*url.URL, error) {
+			return nil, fmt.Errorf("bad proxy URL %q: %w", s, err)
+		}
+	}
+	return http.ProxyURL(u)
+}
+
+// RetryAfter returns the wait asked by the Retry-After header of a
+// response, or 0 if there is no such header.
+func RetryAfter(resp *http.Response) time.Duration {
+	secs, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// Sleep pauses for d or until ctx is canceled.
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
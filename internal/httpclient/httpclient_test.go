@@ -0,0 +1,169 @@
+package httpclient_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sfborg/harvester/internal/httpclient"
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func testConfig(opts ...config.Option) config.Config {
+	opts = append([]config.Option{
+		config.OptHTTPBackoff(time.Millisecond),
+		config.OptUserAgent("harvester-test/v1"),
+	}, opts...)
+	return config.New(opts...)
+}
+
+func TestGet(t *testing.T) {
+	assert := assert.New(t)
+	var requests int
+	var agent string
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			agent = r.Header.Get("User-Agent")
+			if requests < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte("ok"))
+		},
+	))
+	defer srv.Close()
+
+	c := httpclient.New(testConfig())
+	body, err := c.Get(context.Background(), srv.URL)
+	assert.Nil(err)
+	assert.Equal("ok", string(body))
+	assert.Equal(3, requests)
+	assert.Equal("harvester-test/v1", agent)
+}
+
+func TestGetErrors(t *testing.T) {
+	assert := assert.New(t)
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.URL.Path == "/missing" {
+				http.NotFound(w, r)
+				return
+			}
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	))
+	defer srv.Close()
+
+	c := httpclient.New(testConfig(config.OptHTTPRetries(2)))
+	ctx := context.Background()
+
+	_, err := c.Get(ctx, srv.URL+"/missing")
+	var se *httpclient.StatusError
+	assert.True(errors.As(err, &se))
+	assert.Equal(http.StatusNotFound, se.Code)
+	assert.Equal(1, requests, "client errors are not retried")
+
+	requests = 0
+	_, err = c.Get(ctx, srv.URL+"/broken")
+	assert.True(errors.As(err, &se))
+	assert.Equal(http.StatusBadGateway, se.Code)
+	assert.Equal(3, requests)
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = c.Get(ctx, srv.URL)
+	assert.ErrorIs(err, context.Canceled)
+}
+
+func TestGetBrokenBody(t *testing.T) {
+	assert := assert.New(t)
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests%2 == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Length", "10")
+			w.Write([]byte("short"))
+		},
+	))
+	defer srv.Close()
+
+	c := httpclient.New(testConfig(config.OptHTTPRetries(2)))
+	_, err := c.Get(context.Background(), srv.URL)
+	var se *httpclient.StatusError
+	assert.True(errors.As(err, &se))
+	assert.Equal(3, requests, "reading of the body shares retries")
+
+	requests = 1
+	_, err = c.Get(context.Background(), srv.URL)
+	assert.ErrorContains(err, "reading response body")
+	assert.Equal(4, requests)
+}
+
+func TestRateLimit(t *testing.T) {
+	assert := assert.New(t)
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {},
+	))
+	defer srv.Close()
+
+	c := httpclient.New(testConfig(config.OptHTTPRateLimit(20)))
+	start := time.Now()
+	for range 4 {
+		_, err := c.Get(context.Background(), srv.URL)
+		assert.Nil(err)
+	}
+	assert.GreaterOrEqual(time.Since(start), 150*time.Millisecond)
+}
+
+func TestProxy(t *testing.T) {
+	assert := assert.New(t)
+	var host string
+	proxy := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			host = r.Host
+			w.Write([]byte("proxied"))
+		},
+	))
+	defer proxy.Close()
+
+	c := httpclient.New(testConfig(config.OptHTTPProxy(proxy.URL)))
+	body, err := c.Get(context.Background(), "http://example.invalid/data")
+	assert.Nil(err)
+	assert.Equal("proxied", string(body))
+	assert.Equal("example.invalid", host)
+}
+
+func TestShared(t *testing.T) {
+	assert := assert.New(t)
+	cfg := testConfig()
+	c := httpclient.Shared(cfg)
+	assert.Same(c, httpclient.Shared(cfg.ForLabel("itis")))
+	assert.NotSame(c, httpclient.Shared(testConfig(config.OptHTTPRetries(1))))
+}
+
+func TestGetFile(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "names?page=1")
+	err := os.WriteFile(path, []byte("local"), 0644)
+	assert.Nil(err)
+
+	c := httpclient.New(testConfig())
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+	body, err := c.Get(context.Background(), u.String())
+	assert.Nil(err)
+	assert.Equal("local", string(body))
+}
@@ -2,13 +2,17 @@ package list
 
 import (
 	"github.com/sfborg/harvester/internal/sources/arctos"
-	"github.com/sfborg/harvester/internal/sources/ipni"
+	"github.com/sfborg/harvester/internal/sources/coldp"
+	"github.com/sfborg/harvester/internal/sources/csv"
+	"github.com/sfborg/harvester/internal/sources/dwca"
 	"github.com/sfborg/harvester/internal/sources/grin"
 	"github.com/sfborg/harvester/internal/sources/ioc"
 	"github.com/sfborg/harvester/internal/sources/ion"
+	"github.com/sfborg/harvester/internal/sources/ipni"
 	"github.com/sfborg/harvester/internal/sources/itis"
 	"github.com/sfborg/harvester/internal/sources/lpsn"
 	"github.com/sfborg/harvester/internal/sources/mycobank"
+	"github.com/sfborg/harvester/internal/sources/names"
 	"github.com/sfborg/harvester/internal/sources/ncbi"
 	"github.com/sfborg/harvester/internal/sources/nzor"
 	"github.com/sfborg/harvester/internal/sources/paleodb"
@@ -24,6 +28,9 @@ func GetDataSets(cfg config.Config) map[string]data.Convertor {
 	//  values are the corresponding data converters.
 	ds := []data.Convertor{
 		arctos.New(cfg),
+		coldp.New(cfg),
+		csv.New(cfg),
+		dwca.New(cfg),
 		ipni.New(cfg),
 		grin.New(cfg),
 		mycobank.New(cfg),
@@ -31,6 +38,7 @@ func GetDataSets(cfg config.Config) map[string]data.Convertor {
 		ioc.New(cfg),
 		itis.New(cfg),
 		lpsn.New(cfg),
+		names.New(cfg),
 		ncbi.New(cfg),
 		nzor.New(cfg),
 		paleodb.New(cfg),
@@ -0,0 +1,23 @@
+package output
+
+import (
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/sfborg/harvester/pkg/diff"
+)
+
+// DiffTable prints the number of changes between two archives by kind.
+func DiffTable(r *diff.Result) {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleLight)
+	t.SetOutputMirror(os.Stdout)
+	header := table.Row{"Change", "Count"}
+	t.AppendHeader(header)
+
+	for _, v := range diff.Kinds {
+		t.AppendRow(table.Row{v, r.Counts[v]})
+	}
+	t.AppendFooter(table.Row{"Total", r.Total()})
+	t.Render()
+}
@@ -0,0 +1,157 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/gnames/gn"
+	"github.com/sfborg/harvester/pkg/event"
+)
+
+// Progress renders events of harvests in the terminal. It is the observer
+// used by the command line interface.
+type Progress struct {
+	mu sync.Mutex
+	w  io.Writer
+
+	// sources are shown in the progress line in the order they appeared,
+	// counts contain numbers of processed records of each of them.
+	sources []string
+	counts  map[string]*counts
+
+	// drawn is true if the progress line is in the terminal.
+	drawn bool
+}
+
+// counts are numbers of processed records of a data source by units.
+type counts struct {
+	units  []string
+	values map[string]int64
+}
+
+// NewProgress creates a Progress observer.
+func NewProgress() *Progress {
+	return &Progress{w: os.Stderr, counts: make(map[string]*counts)}
+}
+
+// stageMessages are shown when stages of a harvest start. Other stages
+// report their start themselves.
+var stageMessages = map[string]string{
+	"extract":  "Extracting files of <em>%s</em>",
+	"init":     "Creating empty SFGA file",
+	"validate": "Validating archive",
+}
+
+// Notify renders the event.
+func (p *Progress) Notify(e event.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if e.Type == event.Processed {
+		p.processed(e)
+		return
+	}
+
+	switch e.Type {
+	case event.StageStarted:
+		msg, ok := stageMessages[e.Stage]
+		if !ok {
+			return
+		}
+		p.endLine()
+		if strings.Contains(msg, "%s") {
+			gn.Message(msg, e.Source)
+		} else {
+			gn.Message(msg)
+		}
+	case event.Downloaded:
+		p.endLine()
+		p.downloaded(e)
+	case event.Info:
+		p.endLine()
+		gn.Info("%s", e.Message)
+	case event.Warning:
+		p.endLine()
+		gn.Warn("%s", e.Message)
+	}
+}
+
+// processed updates the progress line. Data sources harvested at the same
+// time share the line, the last event of a stage of a source prints its
+// final numbers and removes it from the line.
+func (p *Progress) processed(e event.Event) {
+	c, ok := p.counts[e.Source]
+	if !ok {
+		c = &counts{values: make(map[string]int64)}
+		p.counts[e.Source] = c
+		p.sources = append(p.sources, e.Source)
+	}
+	if _, ok := c.values[e.Unit]; !ok {
+		c.units = append(c.units, e.Unit)
+	}
+	c.values[e.Unit] = e.Count
+
+	if !e.Done {
+		p.draw(p.sources)
+		return
+	}
+	p.draw([]string{e.Source})
+	p.endLine()
+	delete(p.counts, e.Source)
+	p.sources = slices.DeleteFunc(p.sources, func(s string) bool {
+		return s == e.Source
+	})
+	if len(p.sources) > 0 {
+		p.draw(p.sources)
+	}
+}
+
+// draw replaces the progress line with numbers of given sources.
+func (p *Progress) draw(sources []string) {
+	parts := make([]string, len(sources))
+	for i, v := range sources {
+		parts[i] = p.counts[v].String()
+		if v != "" {
+			parts[i] = v + ": " + parts[i]
+		}
+	}
+	fmt.Fprint(p.w, "\r", strings.Repeat(" ", 80))
+	fmt.Fprintf(p.w, "\rProcessed %s", strings.Join(parts, "; "))
+	p.drawn = true
+}
+
+// endLine finishes the progress line, if there is one. Numbers of sources
+// are kept, so the line is drawn again by the next event.
+func (p *Progress) endLine() {
+	if !p.drawn {
+		return
+	}
+	fmt.Fprintln(p.w)
+	p.drawn = false
+}
+
+func (c *counts) String() string {
+	parts := make([]string, len(c.units))
+	for i, v := range c.units {
+		parts[i] = humanize.Comma(c.values[v]) + " " + v
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (p *Progress) downloaded(e event.Event) {
+	size := humanize.Bytes(uint64(e.Count))
+	if e.Total <= 0 || e.Count == e.Total {
+		gn.Message("Downloaded %s", size)
+		return
+	}
+	pct := 100 * float64(e.Count) / float64(e.Total)
+	gn.Message("Downloaded %s of %s (%.0f%%), ETA %s",
+		size, humanize.Bytes(uint64(e.Total)), pct,
+		e.Duration.Round(time.Second))
+}
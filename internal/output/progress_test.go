@@ -0,0 +1,42 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/sfborg/harvester/pkg/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressSources(t *testing.T) {
+	assert := assert.New(t)
+	var buf bytes.Buffer
+	p := NewProgress()
+	p.w = &buf
+
+	events := []event.Event{
+		{Source: "itis", Count: 10, Unit: "names"},
+		{Source: "ncbi", Count: 5, Unit: "nodes"},
+		{Source: "itis", Count: 20, Unit: "names"},
+		{Source: "ncbi", Count: 7, Unit: "nodes", Done: true},
+		{Source: "itis", Count: 30, Unit: "names", Done: true},
+	}
+	var lines []string
+	for _, v := range events {
+		v.Type = event.Processed
+		p.Notify(v)
+		out := buf.String()
+		lines = append(lines, out[strings.LastIndex(out, "\r")+1:])
+	}
+	assert.Equal([]string{
+		"Processed itis: 10 names",
+		"Processed itis: 10 names; ncbi: 5 nodes",
+		"Processed itis: 20 names; ncbi: 5 nodes",
+		"Processed itis: 20 names",
+		"Processed itis: 30 names\n",
+	}, lines)
+	assert.Contains(buf.String(), "\rProcessed ncbi: 7 nodes\n")
+	assert.Empty(p.sources)
+	assert.False(p.drawn)
+}
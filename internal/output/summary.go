@@ -0,0 +1,58 @@
+package output
+
+import (
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// SummaryRow describes the outcome of harvesting one data source.
+type SummaryRow struct {
+	Label    string
+	Status   string
+	Duration time.Duration
+	OutPath  string
+	Error    string
+}
+
+// SummaryTable prints outcomes of a batch harvest.
+func SummaryTable(rows []SummaryRow) {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleLight)
+	t.SetOutputMirror(os.Stdout)
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Name: "Output", WidthMax: 40},
+		{Name: "Error", WidthMax: 40},
+	})
+	header := table.Row{"#", "Label", "Status", "Duration", "Output", "Error"}
+	t.AppendHeader(header)
+
+	for i, v := range rows {
+		row := table.Row{
+			i + 1,
+			v.Label,
+			colorStatus(v.Status),
+			v.Duration.Round(time.Second),
+			v.OutPath,
+			v.Error,
+		}
+		t.AppendRow(row)
+		t.AppendSeparator()
+	}
+	t.Render()
+}
+
+func colorStatus(s string) string {
+	switch s {
+	case "ok":
+		return color.GreenString(s)
+	case "failed":
+		return color.RedString(s)
+	case "canceled", "skipped":
+		return color.YellowString(s)
+	default:
+		return s
+	}
+}
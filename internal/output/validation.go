@@ -0,0 +1,37 @@
+package output
+
+import (
+	"os"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/sfborg/harvester/pkg/validate"
+)
+
+// ValidationTable prints integrity issues of an archive by category with
+// a few examples for each of them.
+func ValidationTable(r *validate.Report) {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleLight)
+	t.SetOutputMirror(os.Stdout)
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Name: "Examples", WidthMax: 60},
+	})
+	header := table.Row{"Category", "Count", "Examples"}
+	t.AppendHeader(header)
+
+	for _, v := range r.Categories() {
+		var examples []string
+		for _, iss := range r.Examples[v] {
+			ex := iss.Table + ": " + iss.ID
+			if iss.Ref != "" {
+				ex += " -> " + iss.Ref
+			}
+			examples = append(examples, ex)
+		}
+		row := table.Row{v, r.Counts[v], strings.Join(examples, "\n")}
+		t.AppendRow(row)
+		t.AppendSeparator()
+	}
+	t.Render()
+}
@@ -1,7 +1,7 @@
 package arctos
 
 import (
-	"github.com/sfborg/harvester/internal/base"
+	"github.com/sfborg/harvester/pkg/base"
 	"github.com/sfborg/harvester/pkg/config"
 	"github.com/sfborg/harvester/pkg/data"
 	"github.com/sfborg/sflib/pkg/sfga"
@@ -27,6 +27,7 @@ it separately and provide it with the -f flag:
   harvester get arctos -f path/to/gn_merge.tgz`,
 		URL: "https://arctos.database.museum/cache/gn_merge.tgz",
 	}
+	cfg = cfg.ForLabel(set.Label)
 	res := arctos{
 		cfg:       cfg,
 		Convertor: base.New(cfg, &set),
@@ -0,0 +1,83 @@
+package arctos_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sfborg/harvester/internal/sources/arctos"
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/stretchr/testify/assert"
+	_ "modernc.org/sqlite"
+)
+
+const classification = `scientific_name,name_type,term_type,term
+Bubo bubo,Linnean,author_text,"(Linnaeus, 1758)"
+Bubo bubo,Linnean,family,Strigidae
+Bubo virginianus,Linnean,author_text,"(Gmelin, 1788)"
+Bubo virginianus,Linnean,family,Strigidae
+Strix aluco,Linnean,family,Strigidae
+Strix aluco,Common,common_name,Tawny Owl
+`
+
+const relationships = `scientific_name,related_name,taxon_relationship
+Bubo bubo,Strix bubo,synonym of
+Bubo bubo,Bubo maximus,synonym of
+Bubo bubo,Bubo maximus,synonym of
+Bubo virginianus,Bubo maximus,synonym of
+Strix aluco,Strix sylvatica,synonym of
+`
+
+func TestNameUsageIDs(t *testing.T) {
+	assert := assert.New(t)
+
+	ids := func() []string {
+		dir := t.TempDir()
+		cfg := config.New(config.OptCacheDir(dir))
+		extractDir := cfg.ForLabel("arctos").ExtractDir
+		assert.Nil(os.MkdirAll(extractDir, 0755))
+		files := map[string]string{
+			"globalnames_classification.csv": classification,
+			"globalnames_relationships.csv":  relationships,
+		}
+		for k, v := range files {
+			path := filepath.Join(extractDir, k)
+			assert.Nil(os.WriteFile(path, []byte(v), 0644))
+		}
+
+		a := arctos.New(cfg)
+		ctx := context.Background()
+		sfga, err := a.InitSfga(ctx)
+		assert.Nil(err)
+		assert.Nil(a.ToSfga(ctx, sfga))
+
+		dbPath := filepath.Join(dir, "arctos", "sfga", "schema.sqlite")
+		db, err := sql.Open("sqlite", dbPath)
+		assert.Nil(err)
+		defer db.Close()
+
+		rows, err := db.Query("SELECT col__id FROM name ORDER BY col__id")
+		assert.Nil(err)
+		defer rows.Close()
+		var res []string
+		for rows.Next() {
+			var id string
+			assert.Nil(rows.Scan(&id))
+			res = append(res, id)
+		}
+		return res
+	}
+
+	ids1 := ids()
+	// 3 taxa, 4 synonyms, the repeated synonym of Bubo bubo is rejected.
+	assert.Len(ids1, 7)
+	assert.Equal(ids1, ids(), "IDs are stable")
+
+	seen := make(map[string]struct{})
+	for _, v := range ids1 {
+		seen[v] = struct{}{}
+	}
+	assert.Len(seen, len(ids1), "IDs are unique")
+}
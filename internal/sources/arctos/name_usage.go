@@ -1,19 +1,22 @@
 package arctos
 
 import (
+	"context"
 	"encoding/csv"
 	"fmt"
 	"io"
+	"maps"
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
-	"github.com/dustin/go-humanize"
 	"github.com/gnames/gnlib/ent/nomcode"
 	"github.com/gnames/gnparser"
 	"github.com/gnames/gnuuid"
 	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/harvester/pkg/event"
 	"github.com/sfborg/sflib/pkg/coldp"
 )
 
@@ -22,20 +25,18 @@ type synRec struct {
 	relationship string
 }
 
-func (a *arctos) importNameUsages() error {
-	syns, err := a.loadSynonyms()
+func (a *arctos) importNameUsages(ctx context.Context) error {
+	syns, err := a.loadSynonyms(ctx)
 	if err != nil {
 		return err
 	}
 
-	names, err := a.pivotClassification()
+	names, err := a.pivotClassification(ctx)
 	if err != nil {
 		return err
 	}
 
-	gnp := gnparser.New(gnparser.NewConfig(
-		gnparser.OptWithDetails(true),
-	))
+	gnp := data.NewParser(a.cfg)
 
 	var idCounter int
 	idMap := make(map[string]string)
@@ -53,42 +54,47 @@ func (a *arctos) importNameUsages() error {
 	var batch []coldp.NameUsage
 	total := 0
 
-	for sciName, fields := range names {
-		nu := buildNameUsage(sciName, fields)
-		nu.ID = makeID(sciName)
-
-		data.AddParsedData(gnp, nu)
-
-		if nu.CanonicalFull != "" {
-			nu.NameAlternativeID = "gnoutlink:" + url.QueryEscape(nu.CanonicalFull)
-		} else {
-			nu.NameAlternativeID = "gnoutlink:" + url.QueryEscape(nu.ScientificName)
+	// Names are sorted to keep IDs and samples the same between harvests.
+	smp := data.NewSampler(a.cfg)
+	for _, sciName := range slices.Sorted(maps.Keys(names)) {
+		if smp.Done() {
+			break
 		}
+		if !smp.Keep() {
+			continue
+		}
+		nu := buildNameUsage(sciName, names[sciName])
+		nu.ID = makeID(sciName)
 
 		batch = append(batch, *nu)
 		total++
 
 		if len(batch) >= a.cfg.BatchSize {
-			if err := a.flushBatch(batch, total); err != nil {
+			if err := a.flushBatch(ctx, gnp, batch, total); err != nil {
 				return err
 			}
 			batch = batch[:0]
 		}
 
 		if synList, ok := syns[sciName]; ok {
+			seen := make(map[string]struct{}, len(synList))
 			for _, s := range synList {
 				snu := buildSynonym(s, nu.ID)
-				snu.ID = makeID(s.relatedName)
-				data.AddParsedData(gnp, snu)
-				if snu.CanonicalFull != "" {
-					snu.NameAlternativeID = "gnoutlink:" + url.QueryEscape(snu.CanonicalFull)
-				} else {
-					snu.NameAlternativeID = "gnoutlink:" + url.QueryEscape(snu.ScientificName)
+				// The same name can be a synonym of several taxa.
+				snu.ID = data.DerivedID(a.cfg.Label, nu.ID, s.relatedName)
+				if _, ok := seen[snu.ID]; ok {
+					a.Report().Reject(data.Rejected{
+						Location: "taxon " + sciName,
+						Reason:   "duplicate synonym",
+						Raw:      s.relatedName,
+					})
+					continue
 				}
+				seen[snu.ID] = struct{}{}
 				batch = append(batch, *snu)
 				total++
 				if len(batch) >= a.cfg.BatchSize {
-					if err := a.flushBatch(batch, total); err != nil {
+					if err := a.flushBatch(ctx, gnp, batch, total); err != nil {
 						return err
 					}
 					batch = batch[:0]
@@ -98,23 +104,47 @@ func (a *arctos) importNameUsages() error {
 	}
 
 	if len(batch) > 0 {
-		if err := a.flushBatch(batch, total); err != nil {
+		if err := a.flushBatch(ctx, gnp, batch, total); err != nil {
 			return err
 		}
 	}
-	fmt.Fprintf(os.Stderr, "\n")
+	a.cfg.Notify(event.Event{
+		Type: event.Processed, Count: int64(total), Unit: "names", Done: true,
+	})
 	return nil
 }
 
-func (a *arctos) flushBatch(batch []coldp.NameUsage, total int) error {
-	fmt.Fprint(os.Stderr, "\r", strings.Repeat(" ", 80))
-	fmt.Fprintf(os.Stderr, "\rProcessed %s names", humanize.Comma(int64(total)))
+// flushBatch parses names of the batch, adds links to them and saves the
+// batch to SFGA.
+func (a *arctos) flushBatch(
+	ctx context.Context,
+	gnp gnparser.GNparser,
+	batch []coldp.NameUsage,
+	total int,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data.ParseBatch(gnp, batch)
+	for i := range batch {
+		nu := &batch[i]
+		if nu.CanonicalFull != "" {
+			nu.NameAlternativeID = "gnoutlink:" + url.QueryEscape(nu.CanonicalFull)
+		} else {
+			nu.NameAlternativeID = "gnoutlink:" + url.QueryEscape(nu.ScientificName)
+		}
+	}
+	a.cfg.Notify(event.Event{
+		Type: event.Processed, Count: int64(total), Unit: "names",
+	})
 	return a.sfga.InsertNameUsages(batch)
 }
 
 // loadSynonyms reads globalnames_relationships.csv and returns a map from
 // accepted scientific_name to its synonyms.
-func (a *arctos) loadSynonyms() (map[string][]synRec, error) {
+func (a *arctos) loadSynonyms(
+	ctx context.Context,
+) (map[string][]synRec, error) {
 	path := filepath.Join(a.cfg.ExtractDir, "globalnames_relationships.csv")
 
 	f, err := os.Open(path)
@@ -161,19 +191,31 @@ func (a *arctos) loadSynonyms() (map[string][]synRec, error) {
 
 		count++
 		if count%100_000 == 0 {
-			fmt.Fprintf(os.Stderr, "\r%s", strings.Repeat(" ", 80))
-			fmt.Fprintf(os.Stderr, "\rLoading synonyms: %s rows",
-				humanize.Comma(int64(count)))
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			a.cfg.Notify(event.Event{
+				Type:  event.Processed,
+				Count: int64(count),
+				Unit:  "synonym rows",
+			})
 		}
 	}
-	fmt.Fprintf(os.Stderr, "\n")
+	a.cfg.Notify(event.Event{
+		Type:  event.Processed,
+		Count: int64(count),
+		Unit:  "synonym rows",
+		Done:  true,
+	})
 	return syns, nil
 }
 
 // pivotClassification reads globalnames_classification.csv and pivots the
 // Entity-Attribute-Value rows into a map[scientificName]map[termType]term,
 // keeping only name_type == "Linnean".
-func (a *arctos) pivotClassification() (map[string]map[string]string, error) {
+func (a *arctos) pivotClassification(
+	ctx context.Context,
+) (map[string]map[string]string, error) {
 	path := filepath.Join(a.cfg.ExtractDir, "globalnames_classification.csv")
 
 	f, err := os.Open(path)
@@ -230,12 +272,22 @@ func (a *arctos) pivotClassification() (map[string]map[string]string, error) {
 
 		count++
 		if count%500_000 == 0 {
-			fmt.Fprintf(os.Stderr, "\r%s", strings.Repeat(" ", 80))
-			fmt.Fprintf(os.Stderr, "\rPivoting classification: %s rows",
-				humanize.Comma(int64(count)))
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			a.cfg.Notify(event.Event{
+				Type:  event.Processed,
+				Count: int64(count),
+				Unit:  "classification rows",
+			})
 		}
 	}
-	fmt.Fprintf(os.Stderr, "\n")
+	a.cfg.Notify(event.Event{
+		Type:  event.Processed,
+		Count: int64(count),
+		Unit:  "classification rows",
+		Done:  true,
+	})
 	return names, nil
 }
 
@@ -316,4 +368,3 @@ func getField(row []string, idx map[string]int, key string) string {
 	}
 	return strings.TrimSpace(row[i])
 }
-
@@ -1,25 +1,25 @@
 package arctos
 
 import (
+	"context"
 	"log/slog"
 
-	"github.com/gnames/gn"
 	"github.com/sfborg/sflib/pkg/sfga"
 )
 
-func (a *arctos) ToSfga(sfga sfga.Archive) error {
+func (a *arctos) ToSfga(ctx context.Context, sfga sfga.Archive) error {
 	var err error
 	a.sfga = sfga
 
 	slog.Info("importing Meta")
-	gn.Info("Importing Meta")
+	a.cfg.Info("Importing Meta")
 	if err = a.importMeta(); err != nil {
 		return err
 	}
 
 	slog.Info("importing Name Usages")
-	gn.Info("Importing Name Usages")
-	if err = a.importNameUsages(); err != nil {
+	a.cfg.Info("Importing Name Usages")
+	if err = a.importNameUsages(ctx); err != nil {
 		return err
 	}
 
@@ -0,0 +1,80 @@
+package coldp
+
+import (
+	"context"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/gnames/gnsys"
+	"github.com/sfborg/harvester/pkg/base"
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/sfborg/harvester/pkg/data"
+	sfcoldp "github.com/sfborg/sflib/pkg/coldp"
+	"github.com/sfborg/sflib/pkg/sfga"
+)
+
+type coldp struct {
+	data.Convertor
+	cfg  config.Config
+	sfga sfga.Archive
+	arc  sfcoldp.Archive
+}
+
+func New(cfg config.Config) data.Convertor {
+	set := data.DataSet{
+		Label: "coldp",
+		Name:  "Catalogue of Life Data Package",
+		Notes: `Imports a CoLDP archive or directory. Names are parsed again with
+GNparser, metadata are taken from metadata.yaml. Provide a local file,
+directory or URL of the archive:
+
+  harvester get coldp -f checklist-coldp.zip`,
+		ManualSteps: true,
+		URL:         "",
+	}
+	cfg = cfg.ForLabel(set.Label)
+	res := coldp{
+		cfg:       cfg,
+		Convertor: base.New(cfg, &set),
+	}
+	return &res
+}
+
+// Extract copies a CoLDP directory to the extract directory. Archives are
+// extracted as usual.
+func (c *coldp) Extract(ctx context.Context, path string) error {
+	if path == "" || !isDir(path) {
+		return c.Convertor.Extract(ctx, path)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	slog.Info("copying CoLDP directory", "path", path)
+	c.cfg.Info("Copying CoLDP directory")
+	return copyDir(path, c.cfg.ExtractDir)
+}
+
+func isDir(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.IsDir()
+}
+
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		_, err = gnsys.CopyFile(p, target)
+		return err
+	})
+}
@@ -0,0 +1,91 @@
+package coldp_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sfborg/harvester/internal/sources/coldp"
+	"github.com/sfborg/harvester/internal/sourcetest"
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+var files = map[string]string{
+	"metadata.yaml": `title: Owls of the World
+alias: Owls
+version: "1.0"
+issued: 2020-01-01
+`,
+	"data/NameUsage.tsv": "ID\tparentID\tstatus\tscientificName\tauthorship\trank\n" +
+		"1\t\taccepted\tStrigidae\t\tfamily\n" +
+		"2\t1\taccepted\tBubo bubo\t(Linnaeus, 1758)\t\n" +
+		"3\t2\tsynonym\tStrix bubo\tLinnaeus, 1758\tspecies\n",
+	"data/VernacularName.tsv": "taxonID\tname\tlanguage\n" +
+		"2\tEurasian eagle-owl\teng\n",
+	"data/Distribution.tsv": "taxonID\tarea\tgazetteer\tstatus\n" +
+		"2\tEurope\ttext\tnative\n",
+	"data/Reference.tsv": "ID\tcitation\n" +
+		"ref1\tMikkola, H. 2012. Owls of the World.\n",
+}
+
+func TestColdp(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	src := filepath.Join(dir, "owls")
+	for k, v := range files {
+		path := filepath.Join(src, k)
+		assert.Nil(os.MkdirAll(filepath.Dir(path), 0755))
+		assert.Nil(os.WriteFile(path, []byte(v), 0644))
+	}
+
+	cfg := config.New(
+		config.OptCacheDir(filepath.Join(dir, "cache")),
+		config.OptArchiveVersion("2.0"),
+	)
+	c := coldp.New(cfg)
+	assert.Equal("coldp", c.Label())
+
+	arc := sourcetest.Harvest(t, c, src)
+	defer arc.Close()
+
+	db := arc.Db()
+	var title, alias, version, issued string
+	err := db.QueryRow(`
+		SELECT col__title, col__alias, col__version, col__issued FROM metadata`,
+	).Scan(&title, &alias, &version, &issued)
+	assert.Nil(err)
+	assert.Equal("Owls of the World", title)
+	assert.Equal("Owls", alias)
+	assert.Equal("2.0", version, "version is overridden by settings")
+	assert.Equal(cfg.ArchiveDate, issued)
+
+	var count int
+	err = db.QueryRow("SELECT count(*) FROM taxon").Scan(&count)
+	assert.Nil(err)
+	assert.Equal(2, count)
+
+	var canonical, rank, gnID string
+	var card int
+	err = db.QueryRow(`
+		SELECT gn__canonical_simple, col__rank_id, gn__cardinality, gn__id
+		FROM name WHERE col__id = '2'`,
+	).Scan(&canonical, &rank, &card, &gnID)
+	assert.Nil(err)
+	assert.Equal("Bubo bubo", canonical)
+	assert.Equal("SPECIES", rank, "rank is taken from the parser")
+	assert.Equal(2, card)
+	assert.NotEmpty(gnID)
+
+	var taxonID string
+	err = db.QueryRow("SELECT col__taxon_id FROM synonym WHERE col__id = '3'").
+		Scan(&taxonID)
+	assert.Nil(err)
+	assert.Equal("2", taxonID)
+
+	for _, tbl := range []string{"vernacular", "distribution", "reference"} {
+		err = db.QueryRow("SELECT count(*) FROM " + tbl).Scan(&count)
+		assert.Nil(err)
+		assert.Equal(1, count, tbl)
+	}
+}
@@ -0,0 +1,155 @@
+package coldp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/harvester/pkg/event"
+	sfcoldp "github.com/sfborg/sflib/pkg/coldp"
+)
+
+// importNames imports names either from NameUsage file, or from Name, Taxon
+// and Synonym files. Names are parsed again to get canonical forms,
+// cardinality and other GNparser data.
+func (c *coldp) importNames(ctx context.Context) error {
+	gnp := data.NewParser(c.cfg)
+
+	paths := c.arc.DataPaths()
+	if _, ok := paths[sfcoldp.NameUsageDT]; ok {
+		return importData(ctx, c, sfcoldp.NameUsageDT, c.sfga.InsertNameUsages,
+			func(nus []sfcoldp.NameUsage) {
+				for i := range nus {
+					nus[i].Rank = parserRank(nus[i].Rank)
+				}
+				data.ParseBatch(gnp, nus)
+			},
+		)
+	}
+
+	if _, ok := paths[sfcoldp.NameDT]; !ok {
+		return fmt.Errorf("cannot find NameUsage or Name data in the archive")
+	}
+	err := importData(ctx, c, sfcoldp.NameDT, c.sfga.InsertNames,
+		func(ns []sfcoldp.Name) {
+			for i := range ns {
+				ns[i].Rank = parserRank(ns[i].Rank)
+			}
+			data.AmendBatch(gnp, ns)
+		},
+	)
+	if err != nil {
+		return err
+	}
+	err = importData(ctx, c, sfcoldp.TaxonDT, c.sfga.InsertTaxa, nil)
+	if err != nil {
+		return err
+	}
+	return importData(ctx, c, sfcoldp.SynonymDT, c.sfga.InsertSynonyms, nil)
+}
+
+// parserRank lets GNparser to set the rank of a name, if the rank is not
+// given. An empty rank is read by CoLDP loaders as 'unranked', and such rank
+// would not be replaced by the rank from the parser.
+func parserRank(r sfcoldp.Rank) sfcoldp.Rank {
+	if r == sfcoldp.Unranked {
+		return sfcoldp.UnknownRank
+	}
+	return r
+}
+
+// importData reads a data file of the given type and inserts its records
+// in batches. If amend is not nil, it modifies every batch before the insert.
+func importData[T sfcoldp.DataLoader](
+	ctx context.Context,
+	c *coldp,
+	dt sfcoldp.DataType,
+	insert func([]T) error,
+	amend func([]T),
+) error {
+	path, ok := c.arc.DataPaths()[dt]
+	if !ok {
+		return nil
+	}
+	slog.Info("importing CoLDP data", "type", dt.ID(), "file", filepath.Base(path))
+
+	// The channel is not closed by Read, because its workers still might
+	// send records if reading fails. The end of reading is signaled by errCh.
+	ch := make(chan T)
+	errCh := make(chan error, 1)
+	go func() {
+		if dt == sfcoldp.ReferenceJsonDT {
+			errCh <- sfcoldp.ReadJSON(path, ch)
+			return
+		}
+		errCh <- sfcoldp.Read(c.arc.Config(), path, ch)
+	}()
+
+	var total int
+	var batch []T
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case rec, ok := <-ch:
+			if !ok {
+				// ReadJSON closes the channel when it is done.
+				ch = nil
+				continue
+			}
+			total++
+			batch = append(batch, rec)
+			if len(batch) < c.cfg.BatchSize {
+				continue
+			}
+			err := flushBatch(c, dt, batch, insert, amend, total)
+			if err != nil {
+				return err
+			}
+			batch = batch[:0]
+		case err := <-errCh:
+			if err != nil {
+				return fmt.Errorf("cannot read %s: %w", filepath.Base(path), err)
+			}
+			if len(batch) > 0 {
+				err = flushBatch(c, dt, batch, insert, amend, total)
+				if err != nil {
+					return err
+				}
+			}
+			if isNames(dt) {
+				c.cfg.Notify(event.Event{
+					Type: event.Processed, Count: int64(total), Unit: "names",
+					Done: true,
+				})
+			}
+			return nil
+		}
+	}
+}
+
+// isNames is true for data types that show progress of name processing.
+func isNames(dt sfcoldp.DataType) bool {
+	return dt == sfcoldp.NameUsageDT || dt == sfcoldp.NameDT
+}
+
+func flushBatch[T any](
+	c *coldp,
+	dt sfcoldp.DataType,
+	batch []T,
+	insert func([]T) error,
+	amend func([]T),
+	total int,
+) error {
+	if amend != nil {
+		amend(batch)
+	}
+	if isNames(dt) {
+		c.cfg.Notify(event.Event{
+			Type: event.Processed, Count: int64(total), Unit: "names",
+		})
+	}
+	return insert(batch)
+}
@@ -0,0 +1,33 @@
+package coldp
+
+import (
+	"log/slog"
+
+	"github.com/sfborg/harvester/pkg/event"
+	sfcoldp "github.com/sfborg/sflib/pkg/coldp"
+)
+
+// importMeta keeps metadata of the CoLDP archive. Issued date and version
+// can be overridden by settings.
+func (c *coldp) importMeta() error {
+	meta, err := c.arc.Meta()
+	if err != nil {
+		slog.Warn("cannot read CoLDP metadata", "error", err)
+		c.cfg.Notify(event.Event{
+			Type:    event.Warning,
+			Message: "Cannot read metadata of the CoLDP archive",
+		})
+		meta = &sfcoldp.Meta{}
+	}
+	if meta.Title == "" {
+		meta.Title = "Catalogue of Life Data Package"
+	}
+
+	if c.cfg.ArchiveDate != "" {
+		meta.Issued = c.cfg.ArchiveDate
+	}
+	if c.cfg.ArchiveVersion != "" {
+		meta.Version = c.cfg.ArchiveVersion
+	}
+	return c.sfga.InsertMeta(meta)
+}
@@ -0,0 +1,118 @@
+package coldp
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sfborg/harvester/pkg/event"
+	"github.com/sfborg/sflib"
+	sflibcfg "github.com/sfborg/sflib/config"
+	sfcoldp "github.com/sfborg/sflib/pkg/coldp"
+	"github.com/sfborg/sflib/pkg/sfga"
+)
+
+// ToSfga imports the CoLDP archive into a SFGA archive.
+func (c *coldp) ToSfga(ctx context.Context, sfga sfga.Archive) error {
+	var err error
+	c.sfga = sfga
+
+	c.arc = sflib.NewColdp(
+		sflibcfg.OptBadRow(c.cfg.BadRow),
+		sflibcfg.OptWithQuotes(!c.cfg.WithoutQuotes),
+		sflibcfg.OptBatchSize(c.cfg.BatchSize),
+	)
+	if err = c.arc.Create(c.cfg.ExtractDir); err != nil {
+		return err
+	}
+	if err = c.arc.DirInfo(); err != nil {
+		return err
+	}
+
+	slog.Info("importing Meta")
+	c.cfg.Info("Importing Meta")
+	if err = c.importMeta(); err != nil {
+		return err
+	}
+
+	slog.Info("importing References")
+	c.cfg.Info("Importing References")
+	if err = c.importReferences(ctx); err != nil {
+		return err
+	}
+
+	slog.Info("importing Names")
+	c.cfg.Info("Importing Names")
+	if err = c.importNames(ctx); err != nil {
+		return err
+	}
+
+	slog.Info("importing other data")
+	c.cfg.Info("Importing other data")
+	if err = c.importOther(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *coldp) importReferences(ctx context.Context) error {
+	err := importData(ctx, c, sfcoldp.ReferenceDT, c.sfga.InsertReferences, nil)
+	if err != nil {
+		return err
+	}
+	err = importData(
+		ctx, c, sfcoldp.ReferenceJsonDT, c.sfga.InsertReferences, nil,
+	)
+	if err != nil {
+		return err
+	}
+	if _, ok := c.arc.DataPaths()[sfcoldp.ReferenceBibtexDT]; ok {
+		slog.Warn("skipping unsupported BibTeX references")
+		c.cfg.Notify(event.Event{
+			Type:    event.Warning,
+			Message: "Skipping references in BibTeX format",
+		})
+	}
+	return nil
+}
+
+// importOther imports the rest of CoLDP data types.
+func (c *coldp) importOther(ctx context.Context) error {
+	s := c.sfga
+	err := importData(ctx, c, sfcoldp.AuthorDT, s.InsertAuthors, nil)
+	if err == nil {
+		err = importData(ctx, c, sfcoldp.NameRelationDT, s.InsertNameRelations, nil)
+	}
+	if err == nil {
+		err = importData(ctx, c, sfcoldp.TypeMaterialDT, s.InsertTypeMaterials, nil)
+	}
+	if err == nil {
+		err = importData(ctx, c, sfcoldp.VernacularNameDT, s.InsertVernaculars, nil)
+	}
+	if err == nil {
+		err = importData(ctx, c, sfcoldp.DistributionDT, s.InsertDistributions, nil)
+	}
+	if err == nil {
+		err = importData(ctx, c, sfcoldp.MediaDT, s.InsertMedia, nil)
+	}
+	if err == nil {
+		err = importData(ctx, c, sfcoldp.TreatmentDT, s.InsertTreatments, nil)
+	}
+	if err == nil {
+		err = importData(ctx, c, sfcoldp.TaxonPropertyDT,
+			s.InsertTaxonProperties, nil)
+	}
+	if err == nil {
+		err = importData(ctx, c, sfcoldp.TaxonConceptRelationDT,
+			s.InsertTaxonConceptRelations, nil)
+	}
+	if err == nil {
+		err = importData(ctx, c, sfcoldp.SpeciesEstimateDT,
+			s.InsertSpeciesEstimates, nil)
+	}
+	if err == nil {
+		err = importData(ctx, c, sfcoldp.SpeciesInteractionDT,
+			s.InsertSpeciesInteractions, nil)
+	}
+	return err
+}
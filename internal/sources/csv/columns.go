@@ -0,0 +1,112 @@
+package csv
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fields of a name usage that can be read from a file.
+const (
+	idF             = "id"
+	parentIDF       = "parentID"
+	scientificNameF = "scientificName"
+	authorshipF     = "authorship"
+	rankF           = "rank"
+	statusF         = "status"
+	acceptedIDF     = "acceptedID"
+	vernacularF     = "vernacular"
+	languageF       = "language"
+)
+
+// aliases are normalized column names that are recognized without a
+// mapping file.
+var aliases = map[string][]string{
+	idF:             {"id", "taxonid", "nameusageid"},
+	parentIDF:       {"parentid", "parentnameusageid"},
+	scientificNameF: {"scientificname", "name"},
+	authorshipF:     {"authorship", "scientificnameauthorship", "author", "authors"},
+	rankF:           {"rank", "taxonrank"},
+	statusF:         {"status", "taxonomicstatus"},
+	acceptedIDF:     {"acceptedid", "acceptednameusageid"},
+	vernacularF:     {"vernacular", "vernacularname", "commonname"},
+	languageF:       {"language", "lang"},
+}
+
+// columns keeps the position of every found field in a row.
+type columns map[string]int
+
+// newColumns finds fields in headers using the mapping file, or aliases of
+// the fields if there is no mapping file.
+func newColumns(headers []string, mapFile string) (columns, error) {
+	res := make(columns)
+	if mapFile == "" {
+		for i, h := range headers {
+			h = normalize(h)
+			for f, names := range aliases {
+				if _, ok := res[f]; !ok && slices.Contains(names, h) {
+					res[f] = i
+				}
+			}
+		}
+	} else {
+		mapping, err := readMapping(mapFile)
+		if err != nil {
+			return nil, err
+		}
+		for f, col := range mapping {
+			idx := slices.IndexFunc(headers, func(h string) bool {
+				return strings.EqualFold(strings.TrimSpace(h), col)
+			})
+			if idx < 0 {
+				return nil, fmt.Errorf("column %q for %s is not in the file", col, f)
+			}
+			res[f] = idx
+		}
+	}
+
+	if _, ok := res[scientificNameF]; !ok {
+		return nil, fmt.Errorf("cannot find scientific names column")
+	}
+	return res, nil
+}
+
+// readMapping reads a YAML file with field: column pairs.
+func readMapping(path string) (map[string]string, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var res map[string]string
+	if err = yaml.Unmarshal(bs, &res); err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", path, err)
+	}
+	for f := range res {
+		if _, ok := aliases[f]; !ok {
+			return nil, fmt.Errorf("unknown field %q in %s", f, path)
+		}
+	}
+	return res, nil
+}
+
+// get returns the value of the field in the row.
+func (c columns) get(row []string, field string) string {
+	idx, ok := c[field]
+	if !ok || idx >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[idx])
+}
+
+// normalize removes namespace, case, spaces and underscores from a column
+// name.
+func normalize(s string) string {
+	if _, after, ok := strings.Cut(s, ":"); ok {
+		s = after
+	}
+	s = strings.ToLower(strings.TrimSpace(s))
+	return strings.NewReplacer("_", "", " ", "", "-", "").Replace(s)
+}
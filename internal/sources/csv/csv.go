@@ -0,0 +1,54 @@
+package csv
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sfborg/harvester/pkg/base"
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/sflib/pkg/sfga"
+)
+
+type csv struct {
+	data.Convertor
+	cfg  config.Config
+	sfga sfga.Archive
+	path string
+}
+
+func New(cfg config.Config) data.Convertor {
+	set := data.DataSet{
+		Label: "csv",
+		Name:  "Generic CSV/TSV/PSV checklist",
+		Notes: `Imports an ad-hoc checklist from a comma-, tab- or pipe-delimited
+file. Columns are found by their names (id, parentID, scientificName,
+authorship, rank, status, acceptedID, vernacular, language, or their
+Darwin Core equivalents). Other names can be mapped with a YAML file:
+
+  scientificName: Name
+  authorship: Author
+  acceptedID: Valid ID
+
+  harvester get csv -f checklist.tsv --columns columns.yaml -c botanical`,
+		ManualSteps: true,
+		URL:         "",
+	}
+	cfg = cfg.ForLabel(set.Label)
+	res := csv{
+		cfg:       cfg,
+		Convertor: base.New(cfg, &set),
+	}
+	return &res
+}
+
+func (c *csv) Extract(ctx context.Context, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	slog.Info("copying CSV file")
+	c.cfg.Info("Copying CSV file")
+	var err error
+	c.path, err = base.CopyToExtractDir(c.cfg, path)
+	return err
+}
@@ -0,0 +1,135 @@
+package csv_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gnames/gnlib/ent/nomcode"
+	"github.com/sfborg/harvester/internal/sources/csv"
+	"github.com/sfborg/harvester/internal/sourcetest"
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+const checklist = `Taxon ID|Parent|Name|Author|Level|State|Valid ID|Common|Lang
+1||Plantae||kingdom|valid|||
+2|1|Rosa|L.|genus|valid|||
+3|2|Rosa canina|L.|species|valid||dog rose|eng
+4||Rosa lutetiana|Léman|species|synonym|3|dogrose|eng
+5||||species|valid|||
+3|2|Rosa canina|L.|species|valid|||
+`
+
+const mapping = `id: Taxon ID
+parentID: Parent
+scientificName: Name
+authorship: Author
+rank: Level
+status: State
+acceptedID: Valid ID
+vernacular: Common
+language: Lang
+`
+
+func TestCSV(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "roses.psv")
+	colsPath := filepath.Join(dir, "columns.yaml")
+	assert.Nil(os.WriteFile(path, []byte(checklist), 0644))
+	assert.Nil(os.WriteFile(colsPath, []byte(mapping), 0644))
+
+	cfg := config.New(
+		config.OptCacheDir(filepath.Join(dir, "cache")),
+		config.OptColSep("|"),
+		config.OptWithoutQuotes(true),
+		config.OptCode(nomcode.Botanical),
+		config.OptColumnsFile(colsPath),
+	)
+	c := csv.New(cfg)
+	assert.Equal("csv", c.Label())
+
+	arc := sourcetest.Harvest(t, c, path)
+	defer arc.Close()
+
+	assert.Equal(
+		map[string]int{"empty scientific name": 1, "duplicate ID": 1},
+		c.Report().Rejected,
+	)
+
+	db := arc.Db()
+
+	var title string
+	err := db.QueryRow("SELECT col__title FROM metadata").Scan(&title)
+	assert.Nil(err)
+	assert.Equal("roses", title)
+
+	var count int
+	err = db.QueryRow("SELECT count(*) FROM taxon").Scan(&count)
+	assert.Nil(err)
+	assert.Equal(3, count)
+
+	var taxonID string
+	err = db.QueryRow(
+		"SELECT col__taxon_id FROM synonym WHERE col__id = '4'",
+	).Scan(&taxonID)
+	assert.Nil(err)
+	assert.Equal("3", taxonID)
+
+	var name, auth, rank string
+	err = db.QueryRow(`
+		SELECT col__scientific_name, col__authorship, col__rank_id
+		FROM name WHERE col__id = '3'`,
+	).Scan(&name, &auth, &rank)
+	assert.Nil(err)
+	assert.Equal("Rosa canina", name)
+	assert.Equal("L.", auth)
+	assert.Equal("SPECIES", rank)
+
+	rows, err := db.Query(
+		"SELECT col__taxon_id, col__name FROM vernacular ORDER BY col__name",
+	)
+	assert.Nil(err)
+	defer rows.Close()
+	var verns []string
+	for rows.Next() {
+		var id, v string
+		assert.Nil(rows.Scan(&id, &v))
+		verns = append(verns, id+":"+v)
+	}
+	assert.Equal([]string{"3:dog rose", "3:dogrose"}, verns)
+}
+
+func TestCSVWithoutIDs(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "owls.tsv")
+	list := "scientificName\tauthorship\n" +
+		"Bubo bubo\t(Linnaeus, 1758)\n" +
+		"Strix aluco\tLinnaeus, 1758\n"
+	assert.Nil(os.WriteFile(path, []byte(list), 0644))
+
+	ids := func(path string) []string {
+		cfg := config.New(config.OptCacheDir(filepath.Join(dir, "cache")))
+		arc := sourcetest.Harvest(t, csv.New(cfg), path)
+		defer arc.Close()
+
+		rows, err := arc.Db().Query("SELECT col__id FROM name ORDER BY col__id")
+		assert.Nil(err)
+		defer rows.Close()
+		var res []string
+		for rows.Next() {
+			var id string
+			assert.Nil(rows.Scan(&id))
+			res = append(res, id)
+		}
+		return res
+	}
+
+	ids1 := ids(path)
+	assert.Len(ids1, 2)
+	assert.Len(ids1[0], 36)
+	// an empty path (skip download mode) uses the file copied before.
+	assert.Equal(ids1, ids(""))
+}
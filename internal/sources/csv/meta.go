@@ -0,0 +1,25 @@
+package csv
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/sfborg/sflib/pkg/coldp"
+)
+
+// importMeta creates metadata from the name of the imported file, as
+// ad-hoc files do not have any.
+func (c *csv) importMeta() error {
+	file := filepath.Base(c.path)
+	meta := coldp.Meta{
+		Title:       strings.TrimSuffix(file, filepath.Ext(file)),
+		Description: "Checklist imported from " + file + ".",
+	}
+	if c.cfg.ArchiveDate != "" {
+		meta.Issued = c.cfg.ArchiveDate
+	}
+	if c.cfg.ArchiveVersion != "" {
+		meta.Version = c.cfg.ArchiveVersion
+	}
+	return c.sfga.InsertMeta(&meta)
+}
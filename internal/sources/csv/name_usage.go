@@ -0,0 +1,202 @@
+package csv
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gnames/gnfmt/gncsv"
+	csvCfg "github.com/gnames/gnfmt/gncsv/config"
+	"github.com/gnames/gnparser"
+	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/harvester/pkg/event"
+	"github.com/sfborg/sflib/pkg/coldp"
+)
+
+func (c *csv) importNameUsages(ctx context.Context) error {
+	var rowNum, total int
+	var batch []coldp.NameUsage
+	var verns []coldp.Vernacular
+	ids := make(map[string]struct{})
+	rep := c.Report()
+
+	gnp := data.NewParser(c.cfg)
+
+	r, err := c.reader()
+	if err != nil {
+		return err
+	}
+	cols, err := newColumns(r.Headers(), c.cfg.ColumnsFile)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ch := make(chan []string)
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := r.Read(ctx, ch)
+		close(ch)
+		errCh <- err
+	}()
+
+	for row := range ch {
+		rowNum++
+		nu := c.buildNameUsage(row, cols)
+		if nu == nil {
+			rep.Reject(data.Rejected{
+				Location: fmt.Sprintf("row %d", rowNum),
+				Reason:   "empty scientific name",
+				Raw:      strings.Join(row, "\t"),
+			})
+			continue
+		}
+		if _, ok := ids[nu.ID]; ok {
+			rep.Reject(data.Rejected{
+				Location: fmt.Sprintf("row %d", rowNum),
+				Reason:   "duplicate ID",
+				Raw:      strings.Join(row, "\t"),
+			})
+			continue
+		}
+		ids[nu.ID] = struct{}{}
+
+		if v := cols.get(row, vernacularF); v != "" {
+			taxonID := nu.ID
+			if data.IsSynonym(nu.TaxonomicStatus) {
+				taxonID = nu.ParentID
+			}
+			verns = append(verns, coldp.Vernacular{
+				TaxonID:  taxonID,
+				Name:     v,
+				Language: cols.get(row, languageF),
+			})
+		}
+
+		total++
+		batch = append(batch, *nu)
+		if len(batch) >= c.cfg.BatchSize {
+			parseBatch(gnp, cols, batch)
+			if err = c.flushBatch(ctx, batch, verns, total); err != nil {
+				return err
+			}
+			batch, verns = batch[:0], verns[:0]
+		}
+	}
+	if err = <-errCh; err != nil {
+		return err
+	}
+
+	if len(batch) > 0 {
+		parseBatch(gnp, cols, batch)
+		if err = c.flushBatch(ctx, batch, verns, total); err != nil {
+			return err
+		}
+	}
+	c.cfg.Notify(event.Event{
+		Type: event.Processed, Count: int64(total), Unit: "names", Done: true,
+	})
+	return nil
+}
+
+// reader creates a reader of the file that uses delimiter, quotes and
+// bad rows settings of the configuration.
+func (c *csv) reader() (gncsv.GnCSV, error) {
+	opts := []csvCfg.Option{
+		csvCfg.OptPath(c.path),
+		csvCfg.OptWithQuotes(!c.cfg.WithoutQuotes),
+		csvCfg.OptBadRowMode(c.cfg.BadRow),
+	}
+	if c.cfg.ColSep != "" {
+		opts = append(opts, csvCfg.OptColSep([]rune(c.cfg.ColSep)[0]))
+	}
+	cfg, err := csvCfg.New(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return gncsv.New(cfg), nil
+}
+
+// parseBatch adds parsed data to name usages. If the file has no
+// authorship column, authorship found by the parser is removed from
+// scientific names.
+func parseBatch(
+	gnp gnparser.GNparser,
+	cols columns,
+	batch []coldp.NameUsage,
+) {
+	data.ParseBatch(gnp, batch)
+	if _, ok := cols[authorshipF]; ok {
+		return
+	}
+	for i := range batch {
+		nu := &batch[i]
+		if nu.Authorship != "" {
+			name := strings.TrimSuffix(nu.ScientificName, nu.Authorship)
+			nu.ScientificName = strings.TrimSpace(name)
+		}
+	}
+}
+
+func (c *csv) flushBatch(
+	ctx context.Context,
+	batch []coldp.NameUsage,
+	verns []coldp.Vernacular,
+	total int,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.cfg.Notify(event.Event{
+		Type: event.Processed, Count: int64(total), Unit: "names",
+	})
+	if err := c.sfga.InsertNameUsages(batch); err != nil {
+		return err
+	}
+	if len(verns) == 0 {
+		return nil
+	}
+	return c.sfga.InsertVernaculars(verns)
+}
+
+// buildNameUsage converts a row into a coldp.NameUsage. Rows without an
+// ID get an ID derived from their parent and name, so it does not depend
+// on the order of rows. Returns nil if the row has no scientific name.
+func (c *csv) buildNameUsage(row []string, cols columns) *coldp.NameUsage {
+	name := cols.get(row, scientificNameF)
+	if name == "" {
+		return nil
+	}
+	auth := cols.get(row, authorshipF)
+	nameStr := name
+	if auth != "" && !strings.HasSuffix(name, auth) {
+		nameStr = name + " " + auth
+	}
+	id := cols.get(row, idF)
+
+	nu := &coldp.NameUsage{
+		ID:                   id,
+		ParentID:             cols.get(row, parentIDF),
+		ScientificName:       name,
+		ScientificNameString: nameStr,
+		Authorship:           auth,
+		Code:                 c.cfg.Code,
+	}
+	if rank := cols.get(row, rankF); rank != "" {
+		nu.Rank = coldp.NewRank(rank)
+	}
+
+	nu.TaxonomicStatus = data.TaxonomicStatus(cols.get(row, statusF))
+	if acceptedID := cols.get(row, acceptedIDF); acceptedID != "" &&
+		acceptedID != id {
+		nu.ParentID = acceptedID
+		if !data.IsSynonym(nu.TaxonomicStatus) {
+			nu.TaxonomicStatus = coldp.SynonymTS
+		}
+	}
+	if nu.ID == "" {
+		nu.ID = data.DerivedID(c.cfg.Label, nu.ParentID, nameStr)
+	}
+	return nu
+}
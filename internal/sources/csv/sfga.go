@@ -0,0 +1,28 @@
+package csv
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sfborg/sflib/pkg/sfga"
+)
+
+// ToSfga imports the CSV file into a SFGA archive.
+func (c *csv) ToSfga(ctx context.Context, sfga sfga.Archive) error {
+	var err error
+	c.sfga = sfga
+
+	slog.Info("importing Meta")
+	c.cfg.Info("Importing Meta")
+	if err = c.importMeta(); err != nil {
+		return err
+	}
+
+	slog.Info("importing Name Usages")
+	c.cfg.Info("Importing Name Usages")
+	if err = c.importNameUsages(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package dwca
+
+import (
+	"github.com/sfborg/harvester/pkg/base"
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/sflib/pkg/sfga"
+)
+
+type dwca struct {
+	data.Convertor
+	cfg  config.Config
+	sfga sfga.Archive
+
+	// dir is the directory of meta.xml in the extracted archive.
+	dir  string
+	meta *archive
+
+	// accepted maps IDs of synonyms to IDs of their accepted taxa.
+	accepted map[string]string
+
+	// taxonRefs maps IDs of taxa to IDs of their references.
+	taxonRefs map[string][]string
+}
+
+func New(cfg config.Config) data.Convertor {
+	set := data.DataSet{
+		Label: "dwca",
+		Name:  "Darwin Core Archive checklist",
+		Notes: `Imports a checklist from a Darwin Core Archive with Taxon core.
+VernacularName, Distribution, Reference and TypesAndSpecimen extensions
+are imported as well. Provide a local file or URL of the archive:
+
+  harvester get dwca -f checklist-dwca.zip`,
+		ManualSteps: true,
+		URL:         "",
+	}
+	cfg = cfg.ForLabel(set.Label)
+	res := dwca{
+		cfg:       cfg,
+		Convertor: base.New(cfg, &set),
+	}
+	return &res
+}
@@ -0,0 +1,187 @@
+package dwca_test
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sfborg/harvester/internal/sources/dwca"
+	"github.com/sfborg/harvester/internal/sourcetest"
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+var files = map[string]string{
+	"checklist/meta.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<archive xmlns="http://rs.tdwg.org/dwc/text/" metadata="eml.xml">
+  <core rowType="http://rs.tdwg.org/dwc/terms/Taxon"
+    fieldsTerminatedBy="\t" fieldsEnclosedBy="" ignoreHeaderLines="1">
+    <files><location>taxon.txt</location></files>
+    <id index="0"/>
+    <field index="1" term="http://rs.tdwg.org/dwc/terms/parentNameUsageID"/>
+    <field index="2" term="http://rs.tdwg.org/dwc/terms/acceptedNameUsageID"/>
+    <field index="3" term="http://rs.tdwg.org/dwc/terms/scientificName"/>
+    <field index="4" term="http://rs.tdwg.org/dwc/terms/taxonRank"/>
+    <field index="5" term="http://rs.tdwg.org/dwc/terms/taxonomicStatus"/>
+    <field index="6" term="http://rs.tdwg.org/dwc/terms/namePublishedIn"/>
+    <field term="http://rs.tdwg.org/dwc/terms/nomenclaturalCode" default="ICZN"/>
+  </core>
+  <extension rowType="http://rs.gbif.org/terms/1.0/VernacularName"
+    fieldsTerminatedBy="\t" fieldsEnclosedBy="" ignoreHeaderLines="1">
+    <files><location>vernacular.txt</location></files>
+    <coreid index="0"/>
+    <field index="1" term="http://rs.tdwg.org/dwc/terms/vernacularName"/>
+    <field index="2" term="http://purl.org/dc/terms/language"/>
+  </extension>
+  <extension rowType="http://rs.gbif.org/terms/1.0/Distribution"
+    fieldsTerminatedBy="," ignoreHeaderLines="0">
+    <files><location>distribution.csv</location></files>
+    <coreid index="0"/>
+    <field index="1" term="http://rs.tdwg.org/dwc/terms/locationID"/>
+    <field index="2" term="http://rs.tdwg.org/dwc/terms/locality"/>
+    <field index="3" term="http://rs.tdwg.org/dwc/terms/establishmentMeans"/>
+  </extension>
+  <extension rowType="http://rs.gbif.org/terms/1.0/Reference"
+    fieldsTerminatedBy="\t" fieldsEnclosedBy="" ignoreHeaderLines="1">
+    <files><location>reference.txt</location></files>
+    <coreid index="0"/>
+    <field index="1" term="http://purl.org/dc/terms/identifier"/>
+    <field index="2" term="http://purl.org/dc/terms/bibliographicCitation"/>
+  </extension>
+  <extension rowType="http://rs.gbif.org/terms/1.0/TypesAndSpecimen"
+    fieldsTerminatedBy="\t" fieldsEnclosedBy="" ignoreHeaderLines="1">
+    <files><location>types.txt</location></files>
+    <coreid index="0"/>
+    <field index="1" term="http://rs.tdwg.org/dwc/terms/typeStatus"/>
+    <field index="2" term="http://rs.tdwg.org/dwc/terms/catalogNumber"/>
+  </extension>
+  <extension rowType="http://rs.gbif.org/terms/1.0/Multimedia"
+    fieldsTerminatedBy="\t" ignoreHeaderLines="1">
+    <files><location>media.txt</location></files>
+    <coreid index="0"/>
+  </extension>
+</archive>`,
+	"checklist/eml.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<eml:eml xmlns:eml="eml://ecoinformatics.org/eml-2.1.1">
+  <dataset>
+    <alternateIdentifier>owls-1</alternateIdentifier>
+    <alternateIdentifier>https://example.org/owls</alternateIdentifier>
+    <title>Owls of the World</title>
+    <abstract><para>A checklist of owls.</para></abstract>
+  </dataset>
+</eml:eml>`,
+	"checklist/taxon.txt": "id\tparent\taccepted\tname\trank\tstatus\tpublished\n" +
+		"1\t\t\tStrigidae\tfamily\taccepted\t\n" +
+		"2\t1\t\tBubo bubo (Linnaeus, 1758)\tspecies\taccepted\t" +
+		"Linnaeus, C. 1758. Systema Naturae.\n" +
+		"3\t\t2\tStrix bubo Linnaeus, 1758\tspecies\tsynonym\t" +
+		"Linnaeus, C. 1758. Systema Naturae.\n" +
+		"4\t1\t\t\tspecies\taccepted\t\n",
+	"checklist/vernacular.txt": "id\tname\tlang\n" +
+		"2\tEurasian eagle-owl\ten\n" +
+		"3\tEagle owl\ten\n",
+	"checklist/distribution.csv": `2,TDWG:EUR,Europe,native
+2,,"Japan, Hokkaido",introduced
+`,
+	"checklist/reference.txt": "id\tidentifier\tcitation\n" +
+		"2\tref1\tMikkola, H. 2012. Owls of the World.\n",
+	"checklist/types.txt": "id\tstatus\tcatalog\n" +
+		"3\tholotype\tNHMUK 1234\n",
+	"checklist/media.txt": "id\n2\n",
+}
+
+func TestDwCA(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "owls.zip")
+	writeZip(t, path)
+
+	cfg := config.New(config.OptCacheDir(filepath.Join(dir, "cache")))
+	d := dwca.New(cfg)
+	assert.Equal("dwca", d.Label())
+
+	arc := sourcetest.Harvest(t, d, path)
+	defer arc.Close()
+	assert.Equal(
+		map[string]int{"empty scientific name": 1}, d.Report().Rejected,
+	)
+
+	db := arc.Db()
+	var title, url string
+	err := db.QueryRow("SELECT col__title, col__url FROM metadata").
+		Scan(&title, &url)
+	assert.Nil(err)
+	assert.Equal("Owls of the World", title)
+	assert.Equal("https://example.org/owls", url)
+
+	var count int
+	err = db.QueryRow("SELECT count(*) FROM taxon").Scan(&count)
+	assert.Nil(err)
+	assert.Equal(2, count)
+
+	var taxonID string
+	err = db.QueryRow("SELECT col__taxon_id FROM synonym WHERE col__id = '3'").
+		Scan(&taxonID)
+	assert.Nil(err)
+	assert.Equal("2", taxonID)
+
+	var refID, nameRefID, code string
+	err = db.QueryRow(`
+		SELECT t.col__reference_id, n.col__reference_id, n.col__code_id
+		FROM taxon t JOIN name n ON n.col__id = t.col__name_id
+		WHERE t.col__id = '2'`,
+	).Scan(&refID, &nameRefID, &code)
+	assert.Nil(err)
+	assert.Equal("ref1", refID)
+	assert.Contains(nameRefID, "sf_")
+	assert.Equal("ZOOLOGICAL", code)
+
+	err = db.QueryRow("SELECT count(*) FROM reference").Scan(&count)
+	assert.Nil(err)
+	assert.Equal(2, count)
+
+	err = db.QueryRow(
+		"SELECT count(*) FROM vernacular WHERE col__taxon_id = '2'",
+	).Scan(&count)
+	assert.Nil(err)
+	assert.Equal(2, count, "vernacular of synonym moves to accepted taxon")
+
+	var area, areaID, gz, status string
+	err = db.QueryRow(`
+		SELECT col__area, col__area_id, col__gazetteer_id, col__status_id
+		FROM distribution WHERE col__area_id = 'EUR'`,
+	).Scan(&area, &areaID, &gz, &status)
+	assert.Nil(err)
+	assert.Equal("Europe", area)
+	assert.Equal("TDWG", gz)
+	assert.Equal("NATIVE", status)
+
+	err = db.QueryRow(
+		"SELECT col__area FROM distribution WHERE col__status_id = 'ALIEN'",
+	).Scan(&area)
+	assert.Nil(err)
+	assert.Equal("Japan, Hokkaido", area)
+
+	var nameID, typeStatus string
+	err = db.QueryRow(
+		"SELECT col__name_id, col__status_id FROM type_material",
+	).Scan(&nameID, &typeStatus)
+	assert.Nil(err)
+	assert.Equal("3", nameID)
+	assert.Equal("HOLOTYPE", typeStatus)
+}
+
+func writeZip(t *testing.T, path string) {
+	f, err := os.Create(path)
+	assert.Nil(t, err)
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		assert.Nil(t, err)
+		_, err = w.Write([]byte(content))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, zw.Close())
+}
@@ -0,0 +1,242 @@
+package dwca
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/sfborg/harvester/pkg/event"
+	"github.com/sfborg/sflib/pkg/coldp"
+)
+
+// importReferences imports Reference extensions. They are imported before
+// the core, so taxa get IDs of their references.
+func (d *dwca) importReferences(ctx context.Context) error {
+	d.taxonRefs = make(map[string][]string)
+	refs := make(map[string]coldp.Reference)
+	for i := range d.meta.Extensions {
+		f := &d.meta.Extensions[i]
+		if f.rowType() != "Reference" {
+			continue
+		}
+		err := d.readFile(ctx, f, func(row []string) error {
+			ref := buildReference(f, row)
+			if ref.ID == "" {
+				return nil
+			}
+			refs[ref.ID] = ref
+			if id := f.get(row, idTerm); id != "" {
+				d.taxonRefs[id] = append(d.taxonRefs[id], ref.ID)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	res := make([]coldp.Reference, 0, len(refs))
+	for _, v := range refs {
+		res = append(res, v)
+	}
+	return d.sfga.InsertReferences(res)
+}
+
+// importExtensions imports VernacularName, Distribution and
+// TypesAndSpecimen extensions. Other extensions are ignored.
+func (d *dwca) importExtensions(ctx context.Context) error {
+	for i := range d.meta.Extensions {
+		f := &d.meta.Extensions[i]
+		var err error
+		switch f.rowType() {
+		case "Reference":
+			continue
+		case "VernacularName":
+			err = importRows(ctx, d, f, d.buildVernacular,
+				d.sfga.InsertVernaculars)
+		case "Distribution":
+			err = importRows(ctx, d, f, d.buildDistribution,
+				d.sfga.InsertDistributions)
+		case "TypesAndSpecimen":
+			var count int
+			build := func(f *fileMeta, row []string) (coldp.TypeMaterial, bool) {
+				count++
+				return buildTypeMaterial(f, row, count)
+			}
+			err = importRows(ctx, d, f, build, d.sfga.InsertTypeMaterials)
+		default:
+			slog.Warn("skipping unsupported extension", "rowType", f.RowType)
+			d.cfg.Notify(event.Event{
+				Type:    event.Warning,
+				Message: "Skipping unsupported extension " + f.rowType(),
+			})
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		slog.Info("imported extension", "rowType", f.rowType())
+	}
+	return nil
+}
+
+// importRows builds records from the rows of an extension file and
+// inserts them in batches.
+func importRows[T any](
+	ctx context.Context,
+	d *dwca,
+	f *fileMeta,
+	build func(*fileMeta, []string) (T, bool),
+	insert func([]T) error,
+) error {
+	var batch []T
+	err := d.readFile(ctx, f, func(row []string) error {
+		rec, ok := build(f, row)
+		if !ok {
+			return nil
+		}
+		batch = append(batch, rec)
+		if len(batch) < d.cfg.BatchSize {
+			return nil
+		}
+		err := insert(batch)
+		batch = batch[:0]
+		return err
+	})
+	if err != nil || len(batch) == 0 {
+		return err
+	}
+	return insert(batch)
+}
+
+// taxonID returns the ID of the taxon an extension row belongs to. Data
+// of synonyms are moved to their accepted taxa.
+func (d *dwca) taxonID(f *fileMeta, row []string) string {
+	id := f.get(row, idTerm)
+	if acceptedID, ok := d.accepted[id]; ok {
+		return acceptedID
+	}
+	return id
+}
+
+func (d *dwca) buildVernacular(
+	f *fileMeta,
+	row []string,
+) (coldp.Vernacular, bool) {
+	res := coldp.Vernacular{
+		TaxonID:  d.taxonID(f, row),
+		Name:     f.get(row, "vernacularName"),
+		Language: f.get(row, "language"),
+		Country:  f.get(row, "countryCode"),
+		Area:     f.get(row, "locality"),
+		Sex:      coldp.NewSex(f.get(row, "sex")),
+		Remarks:  f.get(row, "taxonRemarks"),
+	}
+	return res, res.TaxonID != "" && res.Name != ""
+}
+
+func (d *dwca) buildDistribution(
+	f *fileMeta,
+	row []string,
+) (coldp.Distribution, bool) {
+	res := coldp.Distribution{
+		TaxonID:   d.taxonID(f, row),
+		Area:      f.get(row, "locality"),
+		Gazetteer: coldp.TextGz,
+		Status:    distrStatus(f.get(row, "establishmentMeans")),
+		Remarks:   f.get(row, "occurrenceRemarks"),
+	}
+	if res.Status == coldp.UnknownDistSt {
+		res.Status = distrStatus(f.get(row, "occurrenceStatus"))
+	}
+
+	locID := f.get(row, "locationID")
+	country := f.get(row, "countryCode")
+	switch {
+	case strings.HasPrefix(strings.ToUpper(locID), "TDWG:"):
+		res.AreaID, res.Gazetteer = locID[5:], coldp.TDWG
+	case strings.HasPrefix(strings.ToUpper(locID), "ISO3166"):
+		_, res.AreaID, _ = strings.Cut(locID, ":")
+		res.Gazetteer = coldp.ISO
+	case res.Area == "" && country != "":
+		res.AreaID, res.Gazetteer = country, coldp.ISO
+	}
+	if res.Area == "" {
+		res.Area = res.AreaID
+	}
+	return res, res.TaxonID != "" && res.Area != ""
+}
+
+// distrStatus converts values of establishmentMeans and occurrenceStatus
+// to a distribution status.
+func distrStatus(s string) coldp.DistrStatus {
+	switch strings.ToLower(s) {
+	case "native", "endemic":
+		return coldp.Native
+	case "introduced", "naturalised", "naturalized", "invasive", "alien":
+		return coldp.Alien
+	case "managed", "domesticated", "cultivated":
+		return coldp.Domesticated
+	case "doubtful", "uncertain":
+		return coldp.Uncertain
+	default:
+		return coldp.UnknownDistSt
+	}
+}
+
+func buildReference(f *fileMeta, row []string) coldp.Reference {
+	citation := f.get(row, "bibliographicCitation")
+	if citation == "" {
+		parts := []string{
+			f.get(row, "creator"), f.get(row, "date"), f.get(row, "title"),
+		}
+		citation = strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+	}
+	if citation == "" {
+		return coldp.Reference{}
+	}
+	res := citationRef(citation)
+	if id := f.get(row, "identifier"); id != "" {
+		if strings.HasPrefix(strings.ToLower(id), "http") {
+			res.Link = id
+		} else {
+			res.ID = id
+		}
+	}
+	res.Author = f.get(row, "creator")
+	res.Title = f.get(row, "title")
+	res.Issued = f.get(row, "date")
+	res.Remarks = f.get(row, "description")
+	if res.Link == "" {
+		res.Link = f.get(row, "source")
+	}
+	return res
+}
+
+func buildTypeMaterial(
+	f *fileMeta,
+	row []string,
+	num int,
+) (coldp.TypeMaterial, bool) {
+	res := coldp.TypeMaterial{
+		ID:              "type_" + strconv.Itoa(num),
+		NameID:          f.get(row, idTerm),
+		Citation:        f.get(row, "bibliographicCitation"),
+		Status:          coldp.NewTypeStatus(f.get(row, "typeStatus")),
+		InstitutionCode: f.get(row, "institutionCode"),
+		CatalogNumber:   f.get(row, "catalogNumber"),
+		Locality:        f.get(row, "locality"),
+		Sex:             coldp.NewSex(f.get(row, "sex")),
+		Date:            f.get(row, "eventDate"),
+		Collector:       f.get(row, "recordedBy"),
+		Link:            f.get(row, "source"),
+	}
+	if res.Date == "" {
+		res.Date = f.get(row, "verbatimEventDate")
+	}
+	if res.Citation == "" {
+		res.Citation = f.get(row, "verbatimLabel")
+	}
+	return res, res.NameID != ""
+}
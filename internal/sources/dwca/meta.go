@@ -0,0 +1,201 @@
+package dwca
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sfborg/harvester/pkg/event"
+	"github.com/sfborg/sflib/pkg/coldp"
+)
+
+// archive describes files of a Darwin Core Archive, as given in its
+// meta.xml.
+type archive struct {
+	Metadata   string     `xml:"metadata,attr"`
+	Core       fileMeta   `xml:"core"`
+	Extensions []fileMeta `xml:"extension"`
+}
+
+// fileMeta describes the core file or an extension file.
+type fileMeta struct {
+	RowType           string  `xml:"rowType,attr"`
+	FieldsTerminated  string  `xml:"fieldsTerminatedBy,attr"`
+	FieldsEnclosed    *string `xml:"fieldsEnclosedBy,attr"`
+	IgnoreHeaderLines int     `xml:"ignoreHeaderLines,attr"`
+	Location          string  `xml:"files>location"`
+	ID                *index  `xml:"id"`
+	CoreID            *index  `xml:"coreid"`
+	Fields            []field `xml:"field"`
+
+	terms    map[string]int
+	defaults map[string]string
+}
+
+type index struct {
+	Index int `xml:"index,attr"`
+}
+
+type field struct {
+	Index   *int   `xml:"index,attr"`
+	Term    string `xml:"term,attr"`
+	Default string `xml:"default,attr"`
+}
+
+// idTerm is the name of the id or coreid column of a file.
+const idTerm = "id"
+
+// readArchive finds meta.xml in dir or in its subdirectories and reads it.
+// It returns the directory of meta.xml, where data files are located.
+func readArchive(dir string) (string, *archive, error) {
+	var path string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && d.Name() == "meta.xml" {
+			path = p
+			return fs.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	if path == "" {
+		return "", nil, errors.New("cannot find meta.xml in the archive")
+	}
+
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+	var res archive
+	if err = xml.Unmarshal(bs, &res); err != nil {
+		return "", nil, fmt.Errorf("cannot parse meta.xml: %w", err)
+	}
+	res.Core.init()
+	for i := range res.Extensions {
+		res.Extensions[i].init()
+	}
+	return filepath.Dir(path), &res, nil
+}
+
+func (f *fileMeta) init() {
+	f.terms = make(map[string]int)
+	f.defaults = make(map[string]string)
+	for _, v := range f.Fields {
+		t := shortTerm(v.Term)
+		if v.Index != nil {
+			f.terms[t] = *v.Index
+		}
+		if v.Default != "" {
+			f.defaults[t] = v.Default
+		}
+	}
+	switch {
+	case f.ID != nil:
+		f.terms[idTerm] = f.ID.Index
+	case f.CoreID != nil:
+		f.terms[idTerm] = f.CoreID.Index
+	}
+}
+
+// rowType returns the short name of the row type, for example "Taxon".
+func (f *fileMeta) rowType() string {
+	return shortTerm(f.RowType)
+}
+
+// get returns the value of a term in the row, or its default value.
+func (f *fileMeta) get(row []string, term string) string {
+	if idx, ok := f.terms[term]; ok && idx < len(row) {
+		if v := strings.TrimSpace(row[idx]); v != "" {
+			return v
+		}
+	}
+	return f.defaults[term]
+}
+
+// sep returns the field separator. It is a comma by default.
+func (f *fileMeta) sep() rune {
+	s := strings.NewReplacer(`\t`, "\t", `\|`, "|").Replace(f.FieldsTerminated)
+	if s == "" {
+		return ','
+	}
+	return []rune(s)[0]
+}
+
+// quoted returns true if fields can be enclosed by quotes, which is the
+// default.
+func (f *fileMeta) quoted() bool {
+	return f.FieldsEnclosed == nil || *f.FieldsEnclosed != ""
+}
+
+// fieldsNum returns the number of fields in a row.
+func (f *fileMeta) fieldsNum() int {
+	var res int
+	for _, v := range f.terms {
+		res = max(res, v+1)
+	}
+	return res
+}
+
+// shortTerm removes namespace from a term.
+func shortTerm(s string) string {
+	if i := strings.LastIndexAny(s, "/#:"); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
+
+// eml contains fields of EML metadata that are used in SFGA metadata.
+type eml struct {
+	Title    string   `xml:"dataset>title"`
+	Abstract []string `xml:"dataset>abstract>para"`
+	License  string   `xml:"dataset>intellectualRights>para>ulink>citetitle"`
+	URLs     []string `xml:"dataset>alternateIdentifier"`
+}
+
+func (d *dwca) importMeta() error {
+	meta := coldp.Meta{Title: "Darwin Core Archive"}
+
+	var e eml
+	if d.meta.Metadata != "" {
+		bs, err := os.ReadFile(filepath.Join(d.dir, d.meta.Metadata))
+		if err == nil {
+			err = xml.Unmarshal(bs, &e)
+		}
+		if err != nil {
+			slog.Warn("cannot read EML metadata", "file", d.meta.Metadata,
+				"error", err)
+			d.cfg.Notify(event.Event{
+				Type:    event.Warning,
+				Message: "Cannot read metadata from " + d.meta.Metadata,
+			})
+		}
+	}
+	if e.Title != "" {
+		meta.Title = strings.TrimSpace(e.Title)
+	}
+	meta.Description = strings.TrimSpace(strings.Join(e.Abstract, "\n\n"))
+	meta.License = strings.TrimSpace(e.License)
+	for _, v := range e.URLs {
+		if strings.HasPrefix(v, "http") {
+			meta.URL = v
+			break
+		}
+	}
+
+	if d.cfg.ArchiveDate != "" {
+		meta.Issued = d.cfg.ArchiveDate
+	}
+	if d.cfg.ArchiveVersion != "" {
+		meta.Version = d.cfg.ArchiveVersion
+	}
+	return d.sfga.InsertMeta(&meta)
+}
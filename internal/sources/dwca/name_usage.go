@@ -0,0 +1,162 @@
+package dwca
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gnames/gnlib/ent/nomcode"
+	"github.com/gnames/gnparser"
+	"github.com/gnames/gnuuid"
+	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/harvester/pkg/event"
+	"github.com/sfborg/sflib/pkg/coldp"
+)
+
+func (d *dwca) importNameUsages(ctx context.Context) error {
+	core := &d.meta.Core
+	if core.rowType() != "Taxon" {
+		return fmt.Errorf("core %s is not supported, only Taxon", core.RowType)
+	}
+
+	var total int
+	var batch []coldp.NameUsage
+	refs := make(map[string]coldp.Reference)
+	rep := d.Report()
+	d.accepted = make(map[string]string)
+
+	gnp := data.NewParser(d.cfg)
+
+	var rowNum int
+	err := d.readFile(ctx, core, func(row []string) error {
+		rowNum++
+		nu := d.buildNameUsage(core, row)
+		if nu == nil {
+			rep.Reject(data.Rejected{
+				Location: fmt.Sprintf("%s row %d", core.Location, rowNum),
+				Reason:   "empty scientific name",
+				Raw:      strings.Join(row, "\t"),
+			})
+			return nil
+		}
+		if data.IsSynonym(nu.TaxonomicStatus) && nu.ParentID != "" {
+			d.accepted[nu.ID] = nu.ParentID
+		}
+		if citation := core.get(row, "namePublishedIn"); citation != "" {
+			ref := citationRef(citation)
+			refs[ref.ID] = ref
+			nu.NameReferenceID = ref.ID
+		}
+
+		total++
+		batch = append(batch, *nu)
+		if len(batch) >= d.cfg.BatchSize {
+			if err := d.flushBatch(ctx, gnp, batch, total); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(batch) > 0 {
+		if err = d.flushBatch(ctx, gnp, batch, total); err != nil {
+			return err
+		}
+	}
+	d.cfg.Notify(event.Event{
+		Type: event.Processed, Count: int64(total), Unit: "names", Done: true,
+	})
+
+	refSlice := make([]coldp.Reference, 0, len(refs))
+	for _, r := range refs {
+		refSlice = append(refSlice, r)
+	}
+	return d.sfga.InsertReferences(refSlice)
+}
+
+func (d *dwca) flushBatch(
+	ctx context.Context,
+	gnp gnparser.GNparser,
+	batch []coldp.NameUsage,
+	total int,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data.ParseBatch(gnp, batch)
+	d.cfg.Notify(event.Event{
+		Type: event.Processed, Count: int64(total), Unit: "names",
+	})
+	return d.sfga.InsertNameUsages(batch)
+}
+
+// buildNameUsage converts a row of Taxon core into a coldp.NameUsage.
+// Returns nil if the row has no scientific name.
+func (d *dwca) buildNameUsage(f *fileMeta, row []string) *coldp.NameUsage {
+	name := f.get(row, "scientificName")
+	if name == "" {
+		return nil
+	}
+	id := f.get(row, idTerm)
+	if id == "" {
+		id = f.get(row, "taxonID")
+	}
+	auth := f.get(row, "scientificNameAuthorship")
+	nameStr := name
+	if auth != "" && !strings.HasSuffix(name, auth) {
+		nameStr = name + " " + auth
+	}
+
+	code := d.cfg.Code
+	if s := f.get(row, "nomenclaturalCode"); s != "" {
+		code = nomcode.New(s)
+	}
+
+	nu := &coldp.NameUsage{
+		ID:                   id,
+		ParentID:             f.get(row, "parentNameUsageID"),
+		ScientificName:       name,
+		ScientificNameString: nameStr,
+		Authorship:           auth,
+		TaxonomicStatus:      data.TaxonomicStatus(f.get(row, "taxonomicStatus")),
+		NameStatus:           coldp.NewNomStatus(f.get(row, "nomenclaturalStatus")),
+		Code:                 code,
+		GenericName:          f.get(row, "genericName"),
+		SpecificEpithet:      f.get(row, "specificEpithet"),
+		InfraspecificEpithet: f.get(row, "infraspecificEpithet"),
+		Kingdom:              f.get(row, "kingdom"),
+		Phylum:               f.get(row, "phylum"),
+		Class:                f.get(row, "class"),
+		Order:                f.get(row, "order"),
+		Family:               f.get(row, "family"),
+		Genus:                f.get(row, "genus"),
+		ReferenceID:          strings.Join(d.taxonRefs[id], ","),
+		Link:                 f.get(row, "references"),
+		Remarks:              f.get(row, "taxonRemarks"),
+	}
+	if rank := f.get(row, "taxonRank"); rank != "" {
+		nu.Rank = coldp.NewRank(rank)
+	}
+
+	if acceptedID := f.get(row, "acceptedNameUsageID"); acceptedID != "" &&
+		acceptedID != id {
+		nu.ParentID = acceptedID
+		if !data.IsSynonym(nu.TaxonomicStatus) {
+			nu.TaxonomicStatus = coldp.SynonymTS
+		}
+	}
+	return nu
+}
+
+// citationRef creates a reference from a citation. Its ID is a UUID v5 of
+// the citation.
+func citationRef(citation string) coldp.Reference {
+	return coldp.Reference{
+		ID:       "sf_" + gnuuid.New(citation).String(),
+		Citation: citation,
+	}
+}
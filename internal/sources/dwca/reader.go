@@ -0,0 +1,75 @@
+package dwca
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gnames/gnfmt/gncsv"
+	csvCfg "github.com/gnames/gnfmt/gncsv/config"
+)
+
+// readFile calls fn for every row of a core or extension file.
+func (d *dwca) readFile(
+	ctx context.Context,
+	f *fileMeta,
+	fn func(row []string) error,
+) error {
+	path := filepath.Join(d.dir, f.Location)
+	headers := make([]string, max(f.fieldsNum(), firstLineFields(path, f.sep())))
+	for i := range headers {
+		headers[i] = fmt.Sprintf("field%d", i)
+	}
+
+	cfg, err := csvCfg.New(
+		csvCfg.OptPath(path),
+		csvCfg.OptHeaders(headers),
+		csvCfg.OptColSep(f.sep()),
+		csvCfg.OptWithQuotes(f.quoted()),
+		csvCfg.OptSkipHeaders(f.IgnoreHeaderLines > 0),
+		csvCfg.OptBadRowMode(d.cfg.BadRow),
+	)
+	if err != nil {
+		return fmt.Errorf("cannot read %s: %w", f.Location, err)
+	}
+	r := gncsv.New(cfg)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ch := make(chan []string)
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := r.Read(ctx, ch)
+		close(ch)
+		errCh <- err
+	}()
+
+	for row := range ch {
+		if err = fn(row); err != nil {
+			return err
+		}
+	}
+	if err = <-errCh; err != nil {
+		return fmt.Errorf("cannot read %s: %w", f.Location, err)
+	}
+	return nil
+}
+
+// firstLineFields returns the number of fields in the first line of a
+// file.
+func firstLineFields(path string, sep rune) int {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	if !sc.Scan() {
+		return 0
+	}
+	return strings.Count(sc.Text(), string(sep)) + 1
+}
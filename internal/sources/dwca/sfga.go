@@ -0,0 +1,45 @@
+package dwca
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sfborg/sflib/pkg/sfga"
+)
+
+// ToSfga imports the Darwin Core Archive into a SFGA archive.
+func (d *dwca) ToSfga(ctx context.Context, sfga sfga.Archive) error {
+	var err error
+	d.sfga = sfga
+
+	d.dir, d.meta, err = readArchive(d.cfg.ExtractDir)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("importing Meta")
+	d.cfg.Info("Importing Meta")
+	if err = d.importMeta(); err != nil {
+		return err
+	}
+
+	slog.Info("importing References")
+	d.cfg.Info("Importing References")
+	if err = d.importReferences(ctx); err != nil {
+		return err
+	}
+
+	slog.Info("importing Name Usages")
+	d.cfg.Info("Importing Name Usages")
+	if err = d.importNameUsages(ctx); err != nil {
+		return err
+	}
+
+	slog.Info("importing Extensions")
+	d.cfg.Info("Importing Extensions")
+	if err = d.importExtensions(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package grin
 
 import (
 	"bufio"
+	"context"
 	"database/sql"
 	"fmt"
 	"log/slog"
@@ -10,12 +11,12 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/gnames/gn"
 	"github.com/gnames/gnlib"
 	"github.com/gnames/gnsys"
-	"github.com/sfborg/harvester/internal/base"
+	"github.com/sfborg/harvester/pkg/base"
 	"github.com/sfborg/harvester/pkg/config"
 	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/harvester/pkg/event"
 	"github.com/sfborg/sflib/pkg/sfga"
 	_ "modernc.org/sqlite"
 )
@@ -37,6 +38,7 @@ and save to the box.com.`,
 		ManualSteps: true,
 		URL:         "https://uofi.box.com/shared/static/xob0fp0hw26hhz5lwdo421wspw9x8qbq.zip",
 	}
+	cfg = cfg.ForLabel(set.Label)
 	res := grin{
 		cfg:       cfg,
 		Convertor: base.New(cfg, &set),
@@ -44,9 +46,9 @@ and save to the box.com.`,
 	return &res
 }
 
-func (g *grin) Extract(path string) error {
+func (g *grin) Extract(ctx context.Context, path string) error {
 	slog.Info("importing GRIN data to a temporary SQLite database")
-	gn.Info("Importing GRIN data to a temporary SQLite database")
+	g.cfg.Info("Importing GRIN data to a temporary SQLite database")
 	err := gnsys.ExtractZip(path, g.cfg.ExtractDir)
 	if err != nil {
 		return err
@@ -64,6 +66,7 @@ func (g *grin) Extract(path string) error {
 
 	_, err = db.Exec("PRAGMA temp_store = MEMORY")
 	if err != nil {
+		db.Close()
 		return err
 	}
 
@@ -71,6 +74,7 @@ func (g *grin) Extract(path string) error {
 	// usually boosts write performance.
 	_, err = db.Exec("PRAGMA journal_mode = WAL")
 	if err != nil {
+		db.Close()
 		return err
 	}
 
@@ -78,8 +82,9 @@ func (g *grin) Extract(path string) error {
 
 	for _, v := range files {
 		file := filepath.Join(g.cfg.ExtractDir, v)
-		err := createTable(db, file)
+		err := g.createTable(ctx, db, file)
 		if err != nil {
+			g.closeDB()
 			return err
 		}
 	}
@@ -87,6 +92,17 @@ func (g *grin) Extract(path string) error {
 	return nil
 }
 
+// closeDB closes the temporary GRIN database, if it is open.
+func (g *grin) closeDB() {
+	if g.db == nil {
+		return
+	}
+	if err := g.db.Close(); err != nil {
+		slog.Warn("cannot close GRIN database", "error", err)
+	}
+	g.db = nil
+}
+
 func grinFiles(dir string) ([]string, error) {
 	files, err := os.ReadDir(dir)
 	if err != nil {
@@ -101,7 +117,11 @@ func grinFiles(dir string) ([]string, error) {
 	return res, nil
 }
 
-func createTable(db *sql.DB, path string) error {
+func (g *grin) createTable(
+	ctx context.Context,
+	db *sql.DB,
+	path string,
+) error {
 	name := filepath.Base(path)
 	name = name[:len(name)-4]
 
@@ -131,12 +151,11 @@ CREATE TABLE %s (
 		return err
 	}
 
-	populateTable(db, scanner, name, headers)
-
-	return nil
+	return g.populateTable(ctx, db, scanner, name, headers)
 }
 
-func populateTable(
+func (g *grin) populateTable(
+	ctx context.Context,
 	db *sql.DB,
 	scanner *bufio.Scanner,
 	table string,
@@ -183,8 +202,11 @@ INSERT INTO %s (%s)
 		for row := range ch {
 			count++
 			if count%10_000 == 0 {
-				fmt.Fprintf(os.Stderr, "\r%s", strings.Repeat(" ", 60))
-				fmt.Fprintf(os.Stderr, "\rTable '%s': processing %d's row", table, count)
+				g.cfg.Notify(event.Event{
+					Type:  event.Processed,
+					Count: int64(count),
+					Unit:  "rows of " + table,
+				})
 			}
 			// Convert []string to []any
 			rowAny := make([]any, len(row))
@@ -205,22 +227,47 @@ INSERT INTO %s (%s)
 	for scanner.Scan() {
 		num++
 		line := scanner.Text()
-		ch <- strings.Split(line, "\t")
-	}
-	if err := scanner.Err(); err != nil {
-		return err
+		row := strings.Split(line, "\t")
+		if len(row) != len(fields) {
+			// the header is the first line of the file
+			g.Report().Reject(data.Rejected{
+				Location: fmt.Sprintf("%s line %d", table, num+1),
+				Reason:   "wrong number of fields",
+				Raw:      line,
+			})
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case ch <- row:
+		}
+		if err != nil {
+			break
+		}
 	}
 	close(ch)
-
 	wg.Wait()
 
+	if err != nil {
+		return err
+	}
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		return err
 	}
 
-	fmt.Fprintf(os.Stderr, "\r%s\r", strings.Repeat(" ", 60))
+	g.cfg.Notify(event.Event{
+		Type:  event.Processed,
+		Count: int64(num),
+		Unit:  "rows of " + table,
+		Done:  true,
+	})
 	slog.Info("imported data", "table", table, "rows", num)
-	gn.Info("Imported %s with %d rows", table, num)
+	g.cfg.Info("Imported %s with %d rows", table, num)
 	return nil
 }
@@ -0,0 +1,38 @@
+package grin
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/stretchr/testify/assert"
+	_ "modernc.org/sqlite"
+)
+
+func TestCreateTable(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "taxonomy_genus.txt")
+	txt := "genus_id\tgenus_name\n" +
+		"1\tAbies\n" +
+		"2\tPicea\textra\n" +
+		"3\n" +
+		"4\tPinus\n"
+	assert.Nil(os.WriteFile(path, []byte(txt), 0644))
+
+	db, err := sql.Open("sqlite", filepath.Join(dir, "grin.sqlite"))
+	assert.Nil(err)
+	defer db.Close()
+
+	g := New(config.New(config.OptCacheDir(dir))).(*grin)
+	assert.Nil(g.createTable(context.Background(), db, path))
+
+	var count int
+	err = db.QueryRow("SELECT count(*) FROM taxonomy_genus").Scan(&count)
+	assert.Nil(err)
+	assert.Equal(2, count)
+	assert.Equal(2, g.Report().Rejected["wrong number of fields"])
+}
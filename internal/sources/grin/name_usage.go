@@ -1,10 +1,10 @@
 package grin
 
 import (
+	"context"
 	"log/slog"
 	"strings"
 
-	"github.com/gnames/gn"
 	"github.com/gnames/gnlib/ent/nomcode"
 	"github.com/gnames/gnparser"
 	"github.com/gnames/gnuuid"
@@ -12,7 +12,7 @@ import (
 	"github.com/sfborg/sflib/pkg/coldp"
 )
 
-func (g *grin) importNameUsages() error {
+func (g *grin) importNameUsages(ctx context.Context) error {
 	q := `
 SELECT
   s.taxonomy_species_id AS id, s.current_taxonomy_species_id AS accepted_id,
@@ -28,22 +28,18 @@ FROM taxonomy_species s
 	  ON f.taxonomy_family_id = g.taxonomy_family_id
 `
 
-	basionyms, err := g.getBasionyms()
+	basionyms, err := g.getBasionyms(ctx)
 	if err != nil {
 		return err
 	}
-	p := gnparser.New(gnparser.NewConfig(
-		[]gnparser.Option{
-			gnparser.OptWithDetails(true),
-			gnparser.OptCode(nomcode.Botanical),
-		}...))
-	rows, err := g.db.Query(q)
+	p := data.NewParser(g.cfg, gnparser.OptCode(nomcode.Botanical))
+	rows, err := g.db.QueryContext(ctx, q)
 	if err != nil {
 		return err
 	}
 	defer rows.Close()
 	slog.Info("collecting name usages")
-	gn.Info("Collecting name usages")
+	g.cfg.Info("Collecting name usages")
 	var res []coldp.NameUsage
 	refs := make(map[string]string)
 
@@ -108,10 +104,12 @@ FROM taxonomy_species s
 			Modified:             modified,
 		}
 
-		data.AddParsedData(p, &nu)
-
 		res = append(res, nu)
 	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	data.ParseBatch(p, res)
 	err = g.sfga.InsertNameUsages(res)
 	if err != nil {
 		return err
@@ -157,15 +155,17 @@ func getParentID(id, acceptedID string) string {
 	return acceptedID
 }
 
-func (g *grin) getBasionyms() (map[string]string, error) {
+func (g *grin) getBasionyms(
+	ctx context.Context,
+) (map[string]string, error) {
 	slog.Info("getting basionyms")
-	gn.Info("Getting basionyms")
+	g.cfg.Info("Getting basionyms")
 	q := `
 SELECT taxonomy_species_id, current_taxonomy_species_id
 	FROM taxonomy_species
   WHERE synonym_code = 'B'
 `
-	rows, err := g.db.Query(q)
+	rows, err := g.db.QueryContext(ctx, q)
 	if err != nil {
 		return nil, err
 	}
@@ -180,5 +180,8 @@ SELECT taxonomy_species_id, current_taxonomy_species_id
 		}
 		res[current] = basionym
 	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
 	return res, nil
 }
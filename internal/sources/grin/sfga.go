@@ -1,34 +1,36 @@
 package grin
 
 import (
+	"context"
 	"log/slog"
 
-	"github.com/gnames/gn"
 	"github.com/sfborg/sflib/pkg/sfga"
 )
 
-// ToSfga imports the ION archive into a sfga archive.
-func (g *grin) ToSfga(sfga sfga.Archive) error {
+// ToSfga imports the GRIN data into a sfga archive. The temporary GRIN
+// database is closed when conversion is finished or canceled.
+func (g *grin) ToSfga(ctx context.Context, sfga sfga.Archive) error {
 	var err error
 	g.sfga = sfga
+	defer g.closeDB()
 
 	slog.Info("importing Meta data")
-	gn.Info("Importing Meta data")
+	g.cfg.Info("Importing Meta data")
 	err = g.importMeta()
 	if err != nil {
 		return err
 	}
 
 	slog.Info("importing Names")
-	gn.Info("Importing Names")
-	err = g.importNameUsages()
+	g.cfg.Info("Importing Names")
+	err = g.importNameUsages(ctx)
 	if err != nil {
 		return err
 	}
 
 	slog.Info("importing vernaculars")
-	gn.Info("Importing vernaculars")
-	err = g.importVern()
+	g.cfg.Info("Importing vernaculars")
+	err = g.importVern(ctx)
 	if err != nil {
 		return err
 	}
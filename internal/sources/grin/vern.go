@@ -1,19 +1,20 @@
 package grin
 
 import (
+	"context"
 	"strings"
 
 	"github.com/gnames/gnfmt/gnlang"
 	"github.com/sfborg/sflib/pkg/coldp"
 )
 
-func (g *grin) importVern() error {
+func (g *grin) importVern(ctx context.Context) error {
 	q := `
 SELECT name, language_description, taxonomy_species_id
 	FROM taxonomy_common_name
 	WHERE taxonomy_species_id != ''
 `
-	rows, err := g.db.Query(q)
+	rows, err := g.db.QueryContext(ctx, q)
 	if err != nil {
 		return err
 	}
@@ -36,6 +37,9 @@ SELECT name, language_description, taxonomy_species_id
 		}
 		res = append(res, vern)
 	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
 	g.sfga.InsertVernaculars(res)
 	return nil
 }
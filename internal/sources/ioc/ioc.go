@@ -1,12 +1,12 @@
 package ioc
 
 import (
+	"context"
 	"log/slog"
 	"path/filepath"
 
-	"github.com/gnames/gn"
 	"github.com/gnames/gnsys"
-	"github.com/sfborg/harvester/internal/base"
+	"github.com/sfborg/harvester/pkg/base"
 	"github.com/sfborg/harvester/pkg/config"
 	"github.com/sfborg/harvester/pkg/data"
 	"github.com/sfborg/sflib/pkg/sfga"
@@ -29,6 +29,7 @@ and save to the box.com, generate new URL and update it here.`,
 		ManualSteps: true,
 		URL:         "https://uofi.box.com/shared/static/x9f7o161l81my22by0k8ov2kgfmuuunu.tsv",
 	}
+	cfg = cfg.ForLabel(set.Label)
 	res := ioc{
 		cfg:       cfg,
 		Convertor: base.New(cfg, &set),
@@ -36,9 +37,12 @@ and save to the box.com, generate new URL and update it here.`,
 	return &res
 }
 
-func (l *ioc) Extract(path string) error {
+func (l *ioc) Extract(ctx context.Context, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	slog.Info("copying IOC World Birds List")
-	gn.Info("Copying IOC World Birds List")
+	l.cfg.Info("Copying IOC World Birds List")
 	file := filepath.Base(path)
 	l.path = filepath.Join(l.cfg.ExtractDir, file)
 	_, err := gnsys.CopyFile(path, l.path)
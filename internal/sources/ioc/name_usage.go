@@ -18,6 +18,7 @@ import (
 	"github.com/gnames/gnlib/ent/nomcode"
 	"github.com/gnames/gnparser"
 	"github.com/sfborg/harvester/internal/util"
+	"github.com/sfborg/harvester/pkg/data"
 	"github.com/sfborg/sflib/pkg/coldp"
 )
 
@@ -26,8 +27,6 @@ var ranks = []string{
 	"Genus", "Species (Scientific)", "Subspecies",
 }
 
-var parser = gnparser.New(gnparser.NewConfig())
-
 type name struct {
 	id                                       string
 	rank                                     string
@@ -119,8 +118,6 @@ func (n name) usage() *coldp.NameUsage {
 		return nil
 	}
 
-	res.Amend(parser)
-
 	return res
 }
 
@@ -150,7 +147,7 @@ func (n name) clean() name {
 	return n
 }
 
-func (l *ioc) importNameUsages() error {
+func (l *ioc) importNameUsages(ctx context.Context) error {
 	var res coldp.Data
 	ch := make(chan []string)
 	var wg sync.WaitGroup
@@ -187,10 +184,16 @@ func (l *ioc) importNameUsages() error {
 		}
 	}()
 
-	csv.Read(context.Background(), ch)
+	csv.Read(ctx, ch)
 	close(ch)
 
 	wg.Wait()
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	gnp := data.NewParser(l.cfg, gnparser.OptCode(nomcode.Zoological))
+	data.ParseBatch(gnp, res.NameUsages)
 
 	l.sfga.InsertNameUsages(res.NameUsages)
 	l.sfga.InsertVernaculars(res.Vernaculars)
@@ -1,27 +1,27 @@
 package ioc
 
 import (
+	"context"
 	"log/slog"
 
-	"github.com/gnames/gn"
 	"github.com/sfborg/sflib/pkg/sfga"
 )
 
 // ToSfga imports the IOC List into SFGA archive.
-func (l *ioc) ToSfga(sfga sfga.Archive) error {
+func (l *ioc) ToSfga(ctx context.Context, sfga sfga.Archive) error {
 	var err error
 	l.sfga = sfga
 
 	slog.Info("importing Meta")
-	gn.Info("Importing Meta")
+	l.cfg.Info("Importing Meta")
 	err = l.importMeta()
 	if err != nil {
 		return err
 	}
 
 	slog.Info("importing Names")
-	gn.Info("Importing Names")
-	err = l.importNameUsages()
+	l.cfg.Info("Importing Names")
+	err = l.importNameUsages(ctx)
 	if err != nil {
 		return err
 	}
@@ -1,8 +1,9 @@
 package ion
 
 import (
+	"context"
 	"github.com/gnames/gnsys"
-	"github.com/sfborg/harvester/internal/base"
+	"github.com/sfborg/harvester/pkg/base"
 	"github.com/sfborg/harvester/pkg/config"
 	"github.com/sfborg/harvester/pkg/data"
 	"github.com/sfborg/sflib/pkg/sfga"
@@ -23,6 +24,7 @@ Ask Rod Page for an update.`,
 		ManualSteps: true,
 		URL:         "https://uofi.box.com/shared/static/tklh8i6q2kb33g6ki33k6s3is06lo9np.gz",
 	}
+	cfg = cfg.ForLabel(set.Label)
 	res := ion{
 		cfg:       cfg,
 		Convertor: base.New(cfg, &set),
@@ -30,7 +32,10 @@ Ask Rod Page for an update.`,
 	return &res
 }
 
-func (i *ion) Extract(path string) error {
+func (i *ion) Extract(ctx context.Context, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	err := gnsys.ExtractTarGz(path, i.cfg.ExtractDir)
 	if err != nil {
 		return err
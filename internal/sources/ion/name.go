@@ -2,13 +2,14 @@ package ion
 
 import (
 	"bufio"
-	"fmt"
+	"context"
 	"iter"
 	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/dustin/go-humanize"
+	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/harvester/pkg/event"
 	"github.com/sfborg/sflib/pkg/coldp"
 )
 
@@ -16,7 +17,7 @@ import (
 // It uses a scanner to read the file line by line and an iterator function
 // to yield coldp.Name structs. The names are processed in batches of size
 // specified in the configuration.
-func (i *ion) importNames() error {
+func (i *ion) importNames(ctx context.Context) error {
 	f, err := os.Open(filepath.Join(i.cfg.ExtractDir, "ion.tsv"))
 	if err != nil {
 		return err
@@ -30,7 +31,7 @@ func (i *ion) importNames() error {
 		return scanner.Err()
 	}
 
-	iter := nameIterator(scanner)
+	iter := nameIterator(scanner, data.NewSampler(i.cfg))
 
 	var count int
 	names := make([]coldp.Name, 0, i.cfg.BatchSize)
@@ -39,7 +40,7 @@ func (i *ion) importNames() error {
 		count++
 		names = append(names, n)
 		if len(names) == i.cfg.BatchSize {
-			if err := i.processBatch(names, count); err != nil {
+			if err := i.processBatch(ctx, names, count); err != nil {
 				return err
 			}
 			names = names[:0]
@@ -47,7 +48,7 @@ func (i *ion) importNames() error {
 	}
 
 	if len(names) > 0 {
-		if err := i.processBatch(names, count); err != nil {
+		if err := i.processBatch(ctx, names, count); err != nil {
 			return err
 		}
 	}
@@ -56,12 +57,18 @@ func (i *ion) importNames() error {
 		return err
 	}
 
+	i.cfg.Notify(event.Event{
+		Type: event.Processed, Count: int64(count), Unit: "lines", Done: true,
+	})
 	return nil
 }
 
 // nameIterator returns an iterator function that yields coldp.Name
-// structs from a TSV file scanner.
-func nameIterator(scanner *bufio.Scanner) iter.Seq[coldp.Name] {
+// structs from a TSV file scanner. Lines are selected by the sampler.
+func nameIterator(
+	scanner *bufio.Scanner,
+	smp *data.Sampler,
+) iter.Seq[coldp.Name] {
 	return func(yield func(coldp.Name) bool) {
 		fld := struct {
 			id, name, authorship int
@@ -71,7 +78,10 @@ func nameIterator(scanner *bufio.Scanner) iter.Seq[coldp.Name] {
 			authorship: 4,
 		}
 
-		for scanner.Scan() {
+		for !smp.Done() && scanner.Scan() {
+			if !smp.Keep() {
+				continue
+			}
 			line := scanner.Text()
 			row := strings.Split(line, "\t")
 			n := coldp.Name{
@@ -87,9 +97,17 @@ func nameIterator(scanner *bufio.Scanner) iter.Seq[coldp.Name] {
 	}
 }
 
-func (i *ion) processBatch(names []coldp.Name, count int) error {
-	fmt.Fprint(os.Stderr, "\r", strings.Repeat(" ", 80))
-	fmt.Fprintf(os.Stderr, "\rProcessed %s lines", humanize.Comma(int64(count)))
+func (i *ion) processBatch(
+	ctx context.Context,
+	names []coldp.Name,
+	count int,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	i.cfg.Notify(event.Event{
+		Type: event.Processed, Count: int64(count), Unit: "lines",
+	})
 
 	err := i.sfga.InsertNames(names)
 	if err != nil {
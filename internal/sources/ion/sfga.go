@@ -1,27 +1,27 @@
 package ion
 
 import (
+	"context"
 	"log/slog"
 
-	"github.com/gnames/gn"
 	"github.com/sfborg/sflib/pkg/sfga"
 )
 
 // ToSfga imports the ION archive into a sfga archive.
-func (i *ion) ToSfga(sfga sfga.Archive) error {
+func (i *ion) ToSfga(ctx context.Context, sfga sfga.Archive) error {
 	var err error
 	i.sfga = sfga
 
 	slog.Info("importing Meta")
-	gn.Info("Importing Meta")
+	i.cfg.Info("Importing Meta")
 	err = i.importMeta()
 	if err != nil {
 		return err
 	}
 
 	slog.Info("importing Names")
-	gn.Info("Importing Names")
-	err = i.importNames()
+	i.cfg.Info("Importing Names")
+	err = i.importNames(ctx)
 	if err != nil {
 		return err
 	}
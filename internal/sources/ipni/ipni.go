@@ -1,12 +1,12 @@
 package ipni
 
 import (
+	"context"
 	"path/filepath"
 	"strings"
 
-	"github.com/gnames/gn"
 	"github.com/gnames/gnsys"
-	"github.com/sfborg/harvester/internal/base"
+	"github.com/sfborg/harvester/pkg/base"
 	"github.com/sfborg/harvester/pkg/config"
 	"github.com/sfborg/harvester/pkg/data"
 	"github.com/sfborg/sflib/pkg/sfga"
@@ -33,6 +33,7 @@ are accepted via the -f flag:
 		ManualSteps: true,
 		URL:         "",
 	}
+	cfg = cfg.ForLabel(set.Label)
 	res := ipni{
 		cfg:       cfg,
 		Convertor: base.New(cfg, &set),
@@ -40,8 +41,8 @@ are accepted via the -f flag:
 	return &res
 }
 
-func (i *ipni) Extract(path string) error {
-	gn.Info("Extracting IPNI data")
+func (i *ipni) Extract(ctx context.Context, path string) error {
+	i.cfg.Info("Extracting IPNI data")
 
 	if strings.HasSuffix(path, ".csv") {
 		i.csvPath = filepath.Join(i.cfg.ExtractDir, filepath.Base(path))
@@ -50,7 +51,7 @@ func (i *ipni) Extract(path string) error {
 	}
 
 	// .xz — delegate to base (which calls gnsys.ExtractXz)
-	if err := i.Convertor.Extract(path); err != nil {
+	if err := i.Convertor.Extract(ctx, path); err != nil {
 		return err
 	}
 	// After extraction the file is named ipniWebName.csv in the extract dir.
@@ -1,26 +1,24 @@
 package ipni
 
 import (
+	"context"
 	"encoding/csv"
 	"fmt"
 	"io"
 	"os"
 	"strings"
 
-	"github.com/dustin/go-humanize"
 	"github.com/gnames/gnlib/ent/nomcode"
 	"github.com/gnames/gnparser"
 	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/harvester/pkg/event"
 	"github.com/sfborg/sflib/pkg/coldp"
 )
 
 const ipniLinkBase = "https://www.ipni.org/n/"
 
-func (i *ipni) importNameUsages() error {
-	gnp := gnparser.New(gnparser.NewConfig(
-		gnparser.OptCode(nomcode.Botanical),
-		gnparser.OptWithDetails(true),
-	))
+func (i *ipni) importNameUsages(ctx context.Context) error {
+	gnp := data.NewParser(i.cfg, gnparser.OptCode(nomcode.Botanical))
 
 	f, err := os.Open(i.csvPath)
 	if err != nil {
@@ -40,8 +38,9 @@ func (i *ipni) importNameUsages() error {
 
 	var total int
 	var batch []coldp.NameUsage
+	smp := data.NewSampler(i.cfg)
 
-	for {
+	for !smp.Done() {
 		row, err := r.Read()
 		if err == io.EOF {
 			break
@@ -51,15 +50,13 @@ func (i *ipni) importNameUsages() error {
 		}
 
 		nu := buildNameUsage(row, idx)
-		if nu == nil {
+		if nu == nil || !smp.Keep() {
 			continue
 		}
-		data.AddParsedData(gnp, nu)
-
 		total++
 		batch = append(batch, *nu)
 		if len(batch) >= i.cfg.BatchSize {
-			if err := i.flushBatch(batch, total); err != nil {
+			if err := i.flushBatch(ctx, gnp, batch, total); err != nil {
 				return err
 			}
 			batch = batch[:0]
@@ -67,18 +64,30 @@ func (i *ipni) importNameUsages() error {
 	}
 
 	if len(batch) > 0 {
-		if err := i.flushBatch(batch, total); err != nil {
+		if err := i.flushBatch(ctx, gnp, batch, total); err != nil {
 			return err
 		}
 	}
 
-	fmt.Fprintf(os.Stderr, "\n")
+	i.cfg.Notify(event.Event{
+		Type: event.Processed, Count: int64(total), Unit: "names", Done: true,
+	})
 	return nil
 }
 
-func (i *ipni) flushBatch(batch []coldp.NameUsage, total int) error {
-	fmt.Fprint(os.Stderr, "\r", strings.Repeat(" ", 80))
-	fmt.Fprintf(os.Stderr, "\rProcessed %s names", humanize.Comma(int64(total)))
+func (i *ipni) flushBatch(
+	ctx context.Context,
+	gnp gnparser.GNparser,
+	batch []coldp.NameUsage,
+	total int,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data.ParseBatch(gnp, batch)
+	i.cfg.Notify(event.Event{
+		Type: event.Processed, Count: int64(total), Unit: "names",
+	})
 	return i.sfga.InsertNameUsages(batch)
 }
 
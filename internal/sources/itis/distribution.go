@@ -1,12 +1,13 @@
 package itis
 
 import (
+	"context"
 	"strconv"
 
 	"github.com/sfborg/sflib/pkg/coldp"
 )
 
-func (t *itis) importDistributions() error {
+func (t *itis) importDistributions(ctx context.Context) error {
 	// Query geographic distributions for valid taxa only.
 	// COALESCE is used to handle NULL values.
 	q := `
@@ -18,7 +19,7 @@ INNER JOIN taxonomic_units tu ON gd.tsn = tu.tsn
 WHERE tu.name_usage IN ('valid', 'accepted')
 `
 
-	rows, err := t.db.Query(q)
+	rows, err := t.db.QueryContext(ctx, q)
 	if err != nil {
 		return err
 	}
@@ -2,15 +2,18 @@ package itis
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"database/sql"
 	"log/slog"
-	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 
-	"github.com/sfborg/harvester/internal/base"
+	"github.com/gnames/gnparser"
+	"github.com/sfborg/harvester/internal/httpclient"
+	"github.com/sfborg/harvester/pkg/base"
 	"github.com/sfborg/harvester/pkg/config"
 	"github.com/sfborg/harvester/pkg/data"
 	"github.com/sfborg/sflib/pkg/sfga"
@@ -27,6 +30,8 @@ type itis struct {
 	db      *sql.DB
 	dbPath  string
 	extinct map[int]bool
+	http    *httpclient.Client
+	gnp     gnparser.GNparser
 }
 
 // New creates a new ITIS data convertor.
@@ -41,10 +46,13 @@ extinction status, so this data comes from a manually maintained list.`,
 		ManualSteps: false,
 		URL:         "https://itis.gov/downloads/itisSqlite.zip",
 	}
+	cfg = cfg.ForLabel(set.Label)
 	res := itis{
 		cfg:       cfg,
 		Convertor: base.New(cfg, &set),
 		extinct:   make(map[int]bool),
+		http:      httpclient.Shared(cfg),
+		gnp:       data.NewParser(cfg),
 	}
 	return &res
 }
@@ -52,12 +60,12 @@ extinction status, so this data comes from a manually maintained list.`,
 // Extract extracts the ITIS SQLite database from the downloaded ZIP file.
 // If path is empty (skip download mode), it tries to find an existing
 // database in the extract directory.
-func (t *itis) Extract(path string) error {
+func (t *itis) Extract(ctx context.Context, path string) error {
 	slog.Info("Extracting ITIS SQLite database")
 
 	// If path is provided, extract the archive first.
 	if path != "" {
-		err := t.Convertor.Extract(path)
+		err := t.Convertor.Extract(ctx, path)
 		if err != nil {
 			return err
 		}
@@ -81,6 +89,7 @@ func (t *itis) Extract(path string) error {
 	// Set SQLite pragmas for better performance.
 	_, err = db.Exec("PRAGMA temp_store = MEMORY")
 	if err != nil {
+		db.Close()
 		return err
 	}
 
@@ -88,7 +97,11 @@ func (t *itis) Extract(path string) error {
 
 	// Load extinct TSNs from GitHub.
 	slog.Info("Loading extinct TSNs from GitHub")
-	err = t.loadExtinctTSNs()
+	err = t.loadExtinctTSNs(ctx)
+	if ctx.Err() != nil {
+		t.closeDB()
+		return ctx.Err()
+	}
 	if err != nil {
 		slog.Warn("Could not load extinct TSNs", "error", err)
 		// Not fatal - continue without extinction data.
@@ -99,19 +112,25 @@ func (t *itis) Extract(path string) error {
 	return nil
 }
 
-// loadExtinctTSNs downloads and parses the extinct.tsv file from GitHub.
-func (t *itis) loadExtinctTSNs() error {
-	resp, err := http.Get(extinctURL)
-	if err != nil {
-		return err
+// closeDB closes connection to the ITIS database, if it is open.
+func (t *itis) closeDB() {
+	if t.db == nil {
+		return
 	}
-	defer resp.Body.Close()
+	if err := t.db.Close(); err != nil {
+		slog.Warn("cannot close ITIS database", "error", err)
+	}
+	t.db = nil
+}
 
-	if resp.StatusCode != http.StatusOK {
+// loadExtinctTSNs downloads and parses the extinct.tsv file from GitHub.
+func (t *itis) loadExtinctTSNs(ctx context.Context) error {
+	body, err := t.http.Get(ctx, t.cfg.ResolveURL(extinctURL))
+	if err != nil {
 		return err
 	}
 
-	scanner := bufio.NewScanner(resp.Body)
+	scanner := bufio.NewScanner(bytes.NewReader(body))
 
 	// Skip header line.
 	if scanner.Scan() {
@@ -1,7 +1,11 @@
 package itis_test
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
@@ -14,6 +18,21 @@ import (
 
 const testDataDir = "../../../testdata/itis"
 
+// extinctTSV contains extinct TSNs of testdata/itis/ITIS.sqlite, see
+// prune_itis.sql.
+const extinctTSV = `tsn
+2351
+2644
+2715
+15379
+18026
+20147
+20202
+20210
+21263
+21384
+`
+
 func TestITISIntegration(t *testing.T) {
 	assert := assert.New(t)
 
@@ -29,7 +48,7 @@ func TestITISIntegration(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	// Create directory structure expected by harvester.
-	extractDir := filepath.Join(tmpDir, "extract", "itisSqlite")
+	extractDir := filepath.Join(tmpDir, "itis", "extract", "itisSqlite")
 	err = os.MkdirAll(extractDir, 0755)
 	assert.NoError(err)
 
@@ -39,10 +58,19 @@ func TestITISIntegration(t *testing.T) {
 	err = os.WriteFile(filepath.Join(extractDir, "ITIS.sqlite"), testData, 0644)
 	assert.NoError(err)
 
+	// Serve extinct TSNs locally, the test does not need network.
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, extinctTSV)
+		},
+	))
+	defer srv.Close()
+
 	// Create config with temp cache.
 	cfg := config.New(
 		config.OptCacheDir(tmpDir),
 		config.OptSkipDownload(true),
+		config.OptMirrors(map[string]string{"": srv.URL}),
 	)
 
 	// Create ITIS convertor.
@@ -53,20 +81,20 @@ func TestITISIntegration(t *testing.T) {
 	assert.Equal("itis", convertor.Label())
 
 	// Test Extract (opens database, loads extinct TSNs).
-	err = convertor.Extract("")
+	err = convertor.Extract(context.Background(), "")
 	assert.NoError(err, "Extract should succeed")
 
 	// Test InitSfga.
-	sfgaArchive, err := convertor.InitSfga()
+	sfgaArchive, err := convertor.InitSfga(context.Background())
 	assert.NoError(err, "InitSfga should succeed")
 	assert.NotNil(sfgaArchive)
 
 	// Test ToSfga.
-	err = convertor.ToSfga(sfgaArchive)
+	err = convertor.ToSfga(context.Background(), sfgaArchive)
 	assert.NoError(err, "ToSfga should succeed")
 
 	// Verify database was created.
-	sfgaDir := filepath.Join(tmpDir, "sfga")
+	sfgaDir := filepath.Join(tmpDir, "itis", "sfga")
 	dbPath := filepath.Join(sfgaDir, "schema.sqlite")
 	assert.FileExists(dbPath, "SFGA database should be created")
 
@@ -1,19 +1,17 @@
 package itis
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 
 	"github.com/gnames/gnlib/ent/nomcode"
-	"github.com/gnames/gnparser"
 	"github.com/sfborg/harvester/pkg/data"
 	"github.com/sfborg/sflib/pkg/coldp"
 )
 
-var parser = gnparser.New(gnparser.NewConfig(gnparser.OptWithDetails(true)))
-
-func (t *itis) importNameUsages() error {
+func (t *itis) importNameUsages(ctx context.Context) error {
 	// Query to get all accepted taxa with their hierarchy and name information.
 	// This query handles the different name constructions for different ranks.
 	// COALESCE is used to handle NULL values from LEFT JOINs.
@@ -42,7 +40,7 @@ WHERE tu.name_usage IN ('valid', 'accepted')
 	AND (tu.unaccept_reason IS NULL OR tu.unaccept_reason = '')
 `
 
-	rows, err := t.db.Query(q)
+	rows, err := t.db.QueryContext(ctx, q)
 	if err != nil {
 		return err
 	}
@@ -79,6 +77,7 @@ WHERE tu.name_usage IN ('valid', 'accepted')
 		return err
 	}
 
+	data.ParseBatch(t.gnp, nameUsages)
 	err = t.sfga.InsertNameUsages(nameUsages)
 	if err != nil {
 		return err
@@ -144,9 +143,6 @@ func (t *itis) buildNameUsage(
 		nu.Extinct = coldp.ToBool(true)
 	}
 
-	// Parse the name to get additional data.
-	data.AddParsedData(parser, &nu)
-
 	return nu
 }
 
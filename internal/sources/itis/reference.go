@@ -1,13 +1,14 @@
 package itis
 
 import (
+	"context"
 	"strconv"
 	"time"
 
 	"github.com/sfborg/sflib/pkg/coldp"
 )
 
-func (t *itis) importReferences() error {
+func (t *itis) importReferences(ctx context.Context) error {
 	// Query all publications from ITIS.
 	// COALESCE is used to handle NULL values.
 	q := `
@@ -21,7 +22,7 @@ SELECT
 FROM publications
 `
 
-	rows, err := t.db.Query(q)
+	rows, err := t.db.QueryContext(ctx, q)
 	if err != nil {
 		return err
 	}
@@ -7,10 +7,12 @@ import (
 	"github.com/sfborg/sflib/pkg/sfga"
 )
 
-// ToSfga converts the ITIS data to SFGA format.
-func (t *itis) ToSfga(arc sfga.Archive) error {
+// ToSfga converts the ITIS data to SFGA format. The ITIS database is
+// closed when conversion is finished or canceled.
+func (t *itis) ToSfga(ctx context.Context, arc sfga.Archive) error {
 	var err error
 	t.sfga = arc
+	defer t.closeDB()
 
 	slog.Info("Importing Meta")
 	err = t.importMeta()
@@ -19,31 +21,31 @@ func (t *itis) ToSfga(arc sfga.Archive) error {
 	}
 
 	slog.Info("Importing References")
-	err = t.importReferences()
+	err = t.importReferences(ctx)
 	if err != nil {
 		return err
 	}
 
 	slog.Info("Importing Name Usages")
-	err = t.importNameUsages()
+	err = t.importNameUsages(ctx)
 	if err != nil {
 		return err
 	}
 
 	slog.Info("Importing Synonyms")
-	err = t.importSynonyms()
+	err = t.importSynonyms(ctx)
 	if err != nil {
 		return err
 	}
 
 	slog.Info("Importing Vernacular Names")
-	err = t.importVernaculars()
+	err = t.importVernaculars(ctx)
 	if err != nil {
 		return err
 	}
 
 	slog.Info("Importing Distributions")
-	err = t.importDistributions()
+	err = t.importDistributions(ctx)
 	if err != nil {
 		return err
 	}
@@ -52,7 +54,7 @@ func (t *itis) ToSfga(arc sfga.Archive) error {
 	// ITIS doesn't have explicit basionym relationships, so we detect them
 	// by matching stemmed epithets + authorship + year across names.
 	slog.Info("Inferring basionym relationships")
-	err = arc.InferBasionyms(context.Background(), sfga.BasionymInferenceConfig{
+	err = arc.InferBasionyms(ctx, sfga.BasionymInferenceConfig{
 		SkipIfRelationsExist:       true, // skip if relations already exist
 		CreateOriginalCombinations: true, // create OriginalGenus, OriginalSpecies, etc.
 	})
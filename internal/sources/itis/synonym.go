@@ -1,6 +1,7 @@
 package itis
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -9,7 +10,7 @@ import (
 	"github.com/sfborg/sflib/pkg/coldp"
 )
 
-func (t *itis) importSynonyms() error {
+func (t *itis) importSynonyms(ctx context.Context) error {
 	// Query synonyms and invalid names, excluding database artifacts.
 	// COALESCE is used to handle NULL values from LEFT JOINs.
 	q := `
@@ -38,7 +39,7 @@ WHERE COALESCE(tu.unaccept_reason, '') NOT IN (
 )
 `
 
-	rows, err := t.db.Query(q)
+	rows, err := t.db.QueryContext(ctx, q)
 	if err != nil {
 		return err
 	}
@@ -75,6 +76,7 @@ WHERE COALESCE(tu.unaccept_reason, '') NOT IN (
 		return err
 	}
 
+	data.ParseBatch(t.gnp, nameUsages)
 	err = t.sfga.InsertNameUsages(nameUsages)
 	if err != nil {
 		return err
@@ -129,9 +131,6 @@ func (t *itis) buildSynonymUsage(
 		}
 	}
 
-	// Parse the name to get additional data.
-	data.AddParsedData(parser, &nu)
-
 	return nu
 }
 
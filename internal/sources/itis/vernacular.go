@@ -1,12 +1,13 @@
 package itis
 
 import (
+	"context"
 	"strconv"
 
 	"github.com/sfborg/sflib/pkg/coldp"
 )
 
-func (t *itis) importVernaculars() error {
+func (t *itis) importVernaculars(ctx context.Context) error {
 	// Query vernacular names for valid taxa only.
 	// COALESCE is used to handle NULL values.
 	q := `
@@ -19,7 +20,7 @@ INNER JOIN taxonomic_units tu ON tu.tsn = v.tsn
 WHERE tu.name_usage IN ('valid', 'accepted')
 `
 
-	rows, err := t.db.Query(q)
+	rows, err := t.db.QueryContext(ctx, q)
 	if err != nil {
 		return err
 	}
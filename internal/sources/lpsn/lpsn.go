@@ -1,12 +1,12 @@
 package lpsn
 
 import (
+	"context"
 	"log/slog"
 	"path/filepath"
 
-	"github.com/gnames/gn"
 	"github.com/gnames/gnsys"
-	"github.com/sfborg/harvester/internal/base"
+	"github.com/sfborg/harvester/pkg/base"
 	"github.com/sfborg/harvester/pkg/config"
 	"github.com/sfborg/harvester/pkg/data"
 	"github.com/sfborg/sflib/pkg/sfga"
@@ -31,6 +31,7 @@ with the -f flag:
 		ManualSteps: true,
 		URL:         "",
 	}
+	cfg = cfg.ForLabel(set.Label)
 	res := lpsn{
 		cfg:       cfg,
 		Convertor: base.New(cfg, &set),
@@ -38,9 +39,12 @@ with the -f flag:
 	return &res
 }
 
-func (l *lpsn) Extract(path string) error {
+func (l *lpsn) Extract(ctx context.Context, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	slog.Info("copying LPSN CSV file")
-	gn.Info("Copying LPSN CSV file")
+	l.cfg.Info("Copying LPSN CSV file")
 	file := filepath.Base(path)
 	l.path = filepath.Join(l.cfg.ExtractDir, file)
 	_, err := gnsys.CopyFile(path, l.path)
@@ -3,30 +3,26 @@ package lpsn
 import (
 	"context"
 	"fmt"
-	"os"
 	"strings"
 	"sync"
 
-	"github.com/dustin/go-humanize"
 	"github.com/gnames/gnfmt/gncsv"
 	csvCfg "github.com/gnames/gnfmt/gncsv/config"
 	"github.com/gnames/gnlib/ent/nomcode"
 	"github.com/gnames/gnparser"
 	"github.com/gnames/gnuuid"
 	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/harvester/pkg/event"
 	"github.com/sfborg/sflib/pkg/coldp"
 )
 
-func (l *lpsn) importNameUsages() error {
+func (l *lpsn) importNameUsages(ctx context.Context) error {
 	var mu sync.Mutex
 	var count, total int
 	var batch []coldp.NameUsage
 	refs := make(map[string]coldp.Reference)
 
-	gnp := gnparser.New(gnparser.NewConfig(
-		gnparser.OptCode(nomcode.Bacterial),
-		gnparser.OptWithDetails(true),
-	))
+	gnp := data.NewParser(l.cfg, gnparser.OptCode(nomcode.Bacterial))
 
 	ch := make(chan []string)
 	var wg sync.WaitGroup
@@ -41,12 +37,21 @@ func (l *lpsn) importNameUsages() error {
 	}
 	csv := gncsv.New(cfg)
 
+	// readCtx stops reading when the sample is complete.
+	readCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	smp := data.NewSampler(l.cfg)
+
 	go func() {
 		defer wg.Done()
 		for row := range ch {
+			if smp.Done() {
+				cancel()
+				continue
+			}
 			fields := getRow(row, csv.Headers())
 			nu := buildNameUsage(fields)
-			if nu == nil {
+			if nu == nil || !smp.Keep() {
 				continue
 			}
 
@@ -61,15 +66,16 @@ func (l *lpsn) importNameUsages() error {
 				nu.NameReferenceID = refID
 			}
 
-			data.AddParsedData(gnp, nu)
-
 			mu.Lock()
 			count++
 			total++
 			batch = append(batch, *nu)
 			if len(batch) >= l.cfg.BatchSize {
-				if err := l.flushBatch(batch, total); err != nil {
-					fmt.Fprintf(os.Stderr, "\nError flushing batch: %v\n", err)
+				if err := l.flushBatch(ctx, gnp, batch, total); err != nil {
+					l.cfg.Notify(event.Event{
+						Type:    event.Warning,
+						Message: fmt.Sprintf("Error flushing batch: %v", err),
+					})
 				}
 				batch = batch[:0]
 				count = 0
@@ -78,16 +84,21 @@ func (l *lpsn) importNameUsages() error {
 		}
 	}()
 
-	csv.Read(context.Background(), ch)
+	csv.Read(readCtx, ch)
 	close(ch)
 	wg.Wait()
+	if err = ctx.Err(); err != nil {
+		return err
+	}
 
 	if len(batch) > 0 {
-		if err := l.flushBatch(batch, total); err != nil {
+		if err := l.flushBatch(ctx, gnp, batch, total); err != nil {
 			return err
 		}
 	}
-	fmt.Fprintf(os.Stderr, "\n")
+	l.cfg.Notify(event.Event{
+		Type: event.Processed, Count: int64(total), Unit: "names", Done: true,
+	})
 
 	refSlice := make([]coldp.Reference, 0, len(refs))
 	for _, r := range refs {
@@ -96,9 +107,19 @@ func (l *lpsn) importNameUsages() error {
 	return l.sfga.InsertReferences(refSlice)
 }
 
-func (l *lpsn) flushBatch(batch []coldp.NameUsage, total int) error {
-	fmt.Fprint(os.Stderr, "\r", strings.Repeat(" ", 80))
-	fmt.Fprintf(os.Stderr, "\rProcessed %s names", humanize.Comma(int64(total)))
+func (l *lpsn) flushBatch(
+	ctx context.Context,
+	gnp gnparser.GNparser,
+	batch []coldp.NameUsage,
+	total int,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data.ParseBatch(gnp, batch)
+	l.cfg.Notify(event.Event{
+		Type: event.Processed, Count: int64(total), Unit: "names",
+	})
 	return l.sfga.InsertNameUsages(batch)
 }
 
@@ -1,14 +1,14 @@
 package mycobank
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"path/filepath"
 	"strings"
 
-	"github.com/gnames/gn"
 	"github.com/gnames/gnsys"
-	"github.com/sfborg/harvester/internal/base"
+	"github.com/sfborg/harvester/pkg/base"
 	"github.com/sfborg/harvester/pkg/config"
 	"github.com/sfborg/harvester/pkg/data"
 	"github.com/sfborg/sflib/pkg/sfga"
@@ -34,6 +34,7 @@ or MBList.xlsx instead.`,
 		ManualSteps: false,
 		URL:         "https://www.mycobank.org/images/MBList.zip",
 	}
+	cfg = cfg.ForLabel(set.Label)
 	res := mycobank{
 		cfg:       cfg,
 		Convertor: base.New(cfg, &set),
@@ -43,7 +44,7 @@ or MBList.xlsx instead.`,
 
 // Extract handles both a downloaded zip (default) and a user-supplied xlsx
 // file provided via the -f flag.
-func (m *mycobank) Extract(path string) error {
+func (m *mycobank) Extract(ctx context.Context, path string) error {
 	if path == "" {
 		slog.Info("skip extraction (using cached files)")
 		return nil
@@ -51,7 +52,7 @@ func (m *mycobank) Extract(path string) error {
 
 	if strings.HasSuffix(strings.ToLower(path), ".xlsx") {
 		slog.Info("copying MycoBank xlsx file")
-		gn.Info("Copying MycoBank xlsx file")
+		m.cfg.Info("Copying MycoBank xlsx file")
 		dest := filepath.Join(m.cfg.ExtractDir, filepath.Base(path))
 		if _, err := gnsys.CopyFile(path, dest); err != nil {
 			return err
@@ -61,7 +62,7 @@ func (m *mycobank) Extract(path string) error {
 	}
 
 	// Assume zip; delegate to base extractor.
-	if err := m.Convertor.Extract(path); err != nil {
+	if err := m.Convertor.Extract(ctx, path); err != nil {
 		return err
 	}
 
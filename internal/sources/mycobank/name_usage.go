@@ -1,14 +1,15 @@
 package mycobank
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"log/slog"
 	"strings"
 
-	"github.com/dustin/go-humanize"
 	"github.com/gnames/gnlib/ent/nomcode"
 	"github.com/gnames/gnparser"
 	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/harvester/pkg/event"
 	"github.com/sfborg/sflib/pkg/coldp"
 	"github.com/xuri/excelize/v2"
 )
@@ -39,11 +40,8 @@ type mbRow struct {
 	currentMB string // col K – MB# of the accepted name (formula result)
 }
 
-func (m *mycobank) importNameUsages() error {
-	gnp := gnparser.New(gnparser.NewConfig(
-		gnparser.OptCode(nomcode.Botanical),
-		gnparser.OptWithDetails(true),
-	))
+func (m *mycobank) importNameUsages(ctx context.Context) error {
+	gnp := data.NewParser(m.cfg, gnparser.OptCode(nomcode.Botanical))
 
 	f, err := excelize.OpenFile(m.xlsxPath)
 	if err != nil {
@@ -63,13 +61,14 @@ func (m *mycobank) importNameUsages() error {
 	defer rows.Close()
 
 	// Pass 1: collect all rows and build MB# → row-ID index.
-	fmt.Fprint(os.Stderr, "Reading xlsx...")
+	slog.Info("reading xlsx")
 	var allRows []mbRow
 	// mbNumToID maps MycoBank # (col H) → row ID (col A).
 	mbNumToID := make(map[string]string)
 
+	smp := data.NewSampler(m.cfg)
 	rows.Next() // skip header
-	for rows.Next() {
+	for !smp.Done() && rows.Next() {
 		cols, err := rows.Columns()
 		if err != nil {
 			continue
@@ -77,7 +76,7 @@ func (m *mycobank) importNameUsages() error {
 		id := strings.TrimSpace(getCol(cols, colID))
 		mbNum := strings.TrimSpace(getCol(cols, colMBNum))
 		taxonName := strings.TrimSpace(getCol(cols, colTaxonName))
-		if id == "" || taxonName == "" {
+		if id == "" || taxonName == "" || !smp.Keep() {
 			continue
 		}
 
@@ -102,7 +101,12 @@ func (m *mycobank) importNameUsages() error {
 			}
 		}
 	}
-	fmt.Fprintf(os.Stderr, " done (%s rows)\n", humanize.Comma(int64(len(allRows))))
+	m.cfg.Notify(event.Event{
+		Type:  event.Processed,
+		Count: int64(len(allRows)),
+		Unit:  "xlsx rows",
+		Done:  true,
+	})
 
 	// Pass 2: build NameUsages with resolved parent IDs and flush in batches.
 	var total int
@@ -110,12 +114,11 @@ func (m *mycobank) importNameUsages() error {
 
 	for i := range allRows {
 		nu := buildNameUsage(&allRows[i], mbNumToID)
-		data.AddParsedData(gnp, nu)
 
 		total++
 		batch = append(batch, *nu)
 		if len(batch) >= m.cfg.BatchSize {
-			if err := m.flushBatch(batch, total); err != nil {
+			if err := m.flushBatch(ctx, gnp, batch, total); err != nil {
 				return err
 			}
 			batch = batch[:0]
@@ -123,17 +126,29 @@ func (m *mycobank) importNameUsages() error {
 	}
 
 	if len(batch) > 0 {
-		if err := m.flushBatch(batch, total); err != nil {
+		if err := m.flushBatch(ctx, gnp, batch, total); err != nil {
 			return err
 		}
 	}
-	fmt.Fprintf(os.Stderr, "\n")
+	m.cfg.Notify(event.Event{
+		Type: event.Processed, Count: int64(total), Unit: "names", Done: true,
+	})
 	return nil
 }
 
-func (m *mycobank) flushBatch(batch []coldp.NameUsage, total int) error {
-	fmt.Fprint(os.Stderr, "\r", strings.Repeat(" ", 80))
-	fmt.Fprintf(os.Stderr, "\rProcessed %s names", humanize.Comma(int64(total)))
+func (m *mycobank) flushBatch(
+	ctx context.Context,
+	gnp gnparser.GNparser,
+	batch []coldp.NameUsage,
+	total int,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data.ParseBatch(gnp, batch)
+	m.cfg.Notify(event.Event{
+		Type: event.Processed, Count: int64(total), Unit: "names",
+	})
 	return m.sfga.InsertNameUsages(batch)
 }
 
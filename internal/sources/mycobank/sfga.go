@@ -1,25 +1,25 @@
 package mycobank
 
 import (
+	"context"
 	"log/slog"
 
-	"github.com/gnames/gn"
 	"github.com/sfborg/sflib/pkg/sfga"
 )
 
-func (m *mycobank) ToSfga(sfga sfga.Archive) error {
+func (m *mycobank) ToSfga(ctx context.Context, sfga sfga.Archive) error {
 	var err error
 	m.sfga = sfga
 
 	slog.Info("importing Meta")
-	gn.Info("Importing Meta")
+	m.cfg.Info("Importing Meta")
 	if err = m.importMeta(); err != nil {
 		return err
 	}
 
 	slog.Info("importing Name Usages")
-	gn.Info("Importing Name Usages")
-	if err = m.importNameUsages(); err != nil {
+	m.cfg.Info("Importing Name Usages")
+	if err = m.importNameUsages(ctx); err != nil {
 		return err
 	}
 
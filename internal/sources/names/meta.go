@@ -0,0 +1,25 @@
+package names
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/sfborg/sflib/pkg/coldp"
+)
+
+// importMeta creates metadata from the name of the imported file, as
+// ad-hoc files do not have any.
+func (n *names) importMeta() error {
+	file := filepath.Base(n.path)
+	meta := coldp.Meta{
+		Title:       strings.TrimSuffix(file, filepath.Ext(file)),
+		Description: "Names imported from " + file + ".",
+	}
+	if n.cfg.ArchiveDate != "" {
+		meta.Issued = n.cfg.ArchiveDate
+	}
+	if n.cfg.ArchiveVersion != "" {
+		meta.Version = n.cfg.ArchiveVersion
+	}
+	return n.sfga.InsertMeta(&meta)
+}
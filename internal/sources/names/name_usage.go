@@ -0,0 +1,273 @@
+package names
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gnames/gnlib/ent/nomcode"
+	"github.com/gnames/gnparser"
+	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/harvester/pkg/event"
+	"github.com/sfborg/sflib/pkg/coldp"
+)
+
+// parents keeps IDs of uninomials and binomials of the file by their
+// canonical forms, and genera used by other names.
+type parents struct {
+	label  string
+	ids    map[string]string
+	genera map[string]struct{}
+}
+
+func (n *names) importNameUsages(ctx context.Context) error {
+	var total int
+	var batch []coldp.NameUsage
+	ids := make(map[string]struct{})
+	rep := n.Report()
+
+	gnp := data.NewParser(n.cfg)
+
+	var ps *parents
+	if n.cfg.InferGenera {
+		var err error
+		if ps, err = n.findParents(ctx, gnp); err != nil {
+			return err
+		}
+	}
+
+	add := func(nu coldp.NameUsage) error {
+		total++
+		batch = append(batch, nu)
+		if len(batch) < n.cfg.BatchSize {
+			return nil
+		}
+		err := n.flushBatch(ctx, batch, total)
+		batch = batch[:0]
+		return err
+	}
+
+	// process parses names of a chunk of lines and adds them to the batch,
+	// genera inferred for the names go before them.
+	process := func(chunk []coldp.NameUsage) error {
+		data.ParseBatch(gnp, chunk)
+		for i := range chunk {
+			nu := &chunk[i]
+			n.updateNameUsage(nu)
+			if ps != nil {
+				genus := ps.setParent(nu)
+				if genus != nil {
+					if _, ok := ids[genus.ID]; !ok {
+						ids[genus.ID] = struct{}{}
+						data.AddParsedData(gnp, genus)
+						if err := add(*genus); err != nil {
+							return err
+						}
+					}
+				}
+			}
+			if err := add(*nu); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var chunk []coldp.NameUsage
+	err := n.readLines(ctx, func(num int, line string) error {
+		nu := n.buildNameUsage(line)
+		if _, ok := ids[nu.ID]; ok {
+			rep.Reject(data.Rejected{
+				Location: fmt.Sprintf("line %d", num),
+				Reason:   "duplicate name",
+				Raw:      line,
+			})
+			return nil
+		}
+		ids[nu.ID] = struct{}{}
+
+		chunk = append(chunk, *nu)
+		if len(chunk) < n.cfg.BatchSize {
+			return nil
+		}
+		err := process(chunk)
+		chunk = chunk[:0]
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err = process(chunk); err != nil {
+		return err
+	}
+
+	if len(batch) > 0 {
+		if err = n.flushBatch(ctx, batch, total); err != nil {
+			return err
+		}
+	}
+	n.cfg.Notify(event.Event{
+		Type: event.Processed, Count: int64(total), Unit: "names", Done: true,
+	})
+	return nil
+}
+
+// findParents reads the file to find names that can be parents of other
+// names.
+func (n *names) findParents(
+	ctx context.Context,
+	gnp gnparser.GNparser,
+) (*parents, error) {
+	res := parents{
+		label:  n.cfg.Label,
+		ids:    make(map[string]string),
+		genera: make(map[string]struct{}),
+	}
+	var lines []string
+	parse := func() {
+		for _, v := range gnp.ParseNames(lines) {
+			p := v.Flatten()
+			if !p.Parsed {
+				continue
+			}
+			switch p.Cardinality {
+			case 1, 2:
+				if _, ok := res.ids[p.CanonicalSimple]; !ok {
+					res.ids[p.CanonicalSimple] = lineID(n.cfg.Label, p.Verbatim)
+				}
+			}
+			if p.Genus != "" {
+				res.genera[p.Genus] = struct{}{}
+			}
+		}
+		lines = lines[:0]
+	}
+
+	err := n.readLines(ctx, func(_ int, line string) error {
+		lines = append(lines, line)
+		if len(lines) >= n.cfg.BatchSize {
+			parse()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	parse()
+	return &res, nil
+}
+
+// setParent sets the parent of a name usage and the rank of genera. If
+// the genus of a name is not in the file, a new genus record is returned.
+func (ps *parents) setParent(nu *coldp.NameUsage) *coldp.NameUsage {
+	genus := nu.GenericName
+	switch nu.Cardinality.Int64 {
+	case 1:
+		if _, ok := ps.genera[nu.CanonicalSimple]; ok {
+			nu.Rank = coldp.Genus
+		}
+		return nil
+	case 2, 3:
+		if nu.Cardinality.Int64 == 3 {
+			species := genus + " " + nu.SpecificEpithet
+			if id, ok := ps.ids[species]; ok {
+				nu.ParentID = id
+				return nil
+			}
+		}
+		if id, ok := ps.ids[genus]; ok {
+			nu.ParentID = id
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	id := data.DerivedID(ps.label, "", genus)
+	ps.ids[genus] = id
+	nu.ParentID = id
+	return &coldp.NameUsage{
+		ID:                   id,
+		ScientificName:       genus,
+		ScientificNameString: genus,
+		Rank:                 coldp.Genus,
+		TaxonomicStatus:      coldp.AcceptedTS,
+		Code:                 nu.Code,
+	}
+}
+
+// lineID returns the ID of a name usage of a line. It is derived from the
+// label and the line, so it does not change between versions of the file.
+func lineID(label, line string) string {
+	return data.DerivedID(label, "", line)
+}
+
+// buildNameUsage creates a name usage from a line.
+func (n *names) buildNameUsage(line string) *coldp.NameUsage {
+	return &coldp.NameUsage{
+		ID:                   lineID(n.cfg.Label, line),
+		ScientificName:       line,
+		ScientificNameString: line,
+		TaxonomicStatus:      coldp.AcceptedTS,
+		Code:                 n.cfg.Code,
+	}
+}
+
+// updateNameUsage sets the scientific name and rank of a parsed name
+// usage.
+func (n *names) updateNameUsage(nu *coldp.NameUsage) {
+	if nu.CanonicalFull != "" {
+		nu.ScientificName = nu.CanonicalFull
+	}
+	if nu.Cardinality.Int64 == 3 && nu.Rank == coldp.Unranked &&
+		n.cfg.Code == nomcode.Zoological {
+		nu.Rank = coldp.Subspecies
+	}
+}
+
+// readLines calls f for every non-empty line of the file with the number
+// of the line.
+func (n *names) readLines(
+	ctx context.Context,
+	f func(num int, line string) error,
+) error {
+	file, err := os.Open(n.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	sc := bufio.NewScanner(file)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	var num int
+	for sc.Scan() {
+		num++
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		if err = f(num, line); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+func (n *names) flushBatch(
+	ctx context.Context,
+	batch []coldp.NameUsage,
+	total int,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	n.cfg.Notify(event.Event{
+		Type: event.Processed, Count: int64(total), Unit: "names",
+	})
+	return n.sfga.InsertNameUsages(batch)
+}
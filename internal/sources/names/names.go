@@ -0,0 +1,50 @@
+package names
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sfborg/harvester/pkg/base"
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/sflib/pkg/sfga"
+)
+
+type names struct {
+	data.Convertor
+	cfg  config.Config
+	sfga sfga.Archive
+	path string
+}
+
+func New(cfg config.Config) data.Convertor {
+	set := data.DataSet{
+		Label: "names",
+		Name:  "List of scientific names",
+		Notes: `Imports a text file with one scientific name per line. Names are
+parsed with GNparser, use -c to set their nomenclatural code. With
+--infer-genera, genus records are created for species and infraspecific
+names and become their parents:
+
+  harvester get names -f names.txt -c zoological --infer-genera`,
+		ManualSteps: true,
+		URL:         "",
+	}
+	cfg = cfg.ForLabel(set.Label)
+	res := names{
+		cfg:       cfg,
+		Convertor: base.New(cfg, &set),
+	}
+	return &res
+}
+
+func (n *names) Extract(ctx context.Context, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	slog.Info("copying names file")
+	n.cfg.Info("Copying names file")
+	var err error
+	n.path, err = base.CopyToExtractDir(n.cfg, path)
+	return err
+}
@@ -0,0 +1,138 @@
+package names_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gnames/gnlib/ent/nomcode"
+	"github.com/sfborg/harvester/internal/sources/names"
+	"github.com/sfborg/harvester/internal/sourcetest"
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/sflib/pkg/sfga"
+	"github.com/stretchr/testify/assert"
+)
+
+const list = `Bubo Duméril, 1805
+Bubo bubo (Linnaeus, 1758)
+Bubo bubo hispanus Rothschild & Hartert, 1910
+Strix aluco Linnaeus, 1758
+
+Strix aluco Linnaeus, 1758
+Tyto alba alba (Scopoli, 1769)
+`
+
+func TestNames(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg       string
+		infer     bool
+		taxa      int
+		orphans   int
+		strixRank string
+	}{
+		{"no inference", false, 5, 5, ""},
+		{"infer genera", true, 7, 3, "GENUS"},
+	}
+
+	for _, v := range tests {
+		arc := harvest(t, v.infer)
+		db := arc.Db()
+
+		var count int
+		err := db.QueryRow("SELECT count(*) FROM taxon").Scan(&count)
+		assert.Nil(err, v.msg)
+		assert.Equal(v.taxa, count, v.msg)
+
+		err = db.QueryRow(
+			"SELECT count(*) FROM taxon WHERE col__parent_id = ''",
+		).Scan(&count)
+		assert.Nil(err, v.msg)
+		assert.Equal(v.orphans, count, v.msg)
+
+		err = db.QueryRow(`
+			SELECT count(*) FROM taxon
+			WHERE col__parent_id != ''
+			  AND col__parent_id NOT IN (SELECT col__id FROM taxon)`,
+		).Scan(&count)
+		assert.Nil(err, v.msg)
+		assert.Zero(count, v.msg)
+
+		var rank string
+		err = db.QueryRow(`
+			SELECT col__rank_id FROM name
+			WHERE col__scientific_name = 'Strix'`,
+		).Scan(&rank)
+		if v.strixRank == "" {
+			assert.NotNil(err, v.msg)
+		} else {
+			assert.Nil(err, v.msg)
+			assert.Equal(v.strixRank, rank, v.msg)
+		}
+
+		var name, parent string
+		err = db.QueryRow(`
+			SELECT n.col__scientific_name, n.col__rank_id,
+			  coalesce(p.col__scientific_name, '')
+			FROM taxon t
+			  JOIN name n ON n.col__id = t.col__name_id
+			  LEFT JOIN taxon pt ON pt.col__id = t.col__parent_id
+			  LEFT JOIN name p ON p.col__id = pt.col__name_id
+			WHERE n.col__scientific_name = 'Bubo bubo hispanus'`,
+		).Scan(&name, &rank, &parent)
+		assert.Nil(err, v.msg)
+		assert.Equal("SUBSPECIES", rank, v.msg)
+		if v.infer {
+			assert.Equal("Bubo bubo", parent, v.msg)
+
+			var id string
+			err = db.QueryRow(`
+				SELECT t.col__id FROM taxon t
+				  JOIN name n ON n.col__id = t.col__name_id
+				WHERE n.col__scientific_name = 'Strix'`,
+			).Scan(&id)
+			assert.Nil(err, v.msg)
+			assert.Equal(data.DerivedID("names", "", "Strix"), id, v.msg)
+		}
+		arc.Close()
+	}
+}
+
+func harvest(t *testing.T, infer bool) sfga.Archive {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "owls.txt")
+	assert.Nil(os.WriteFile(path, []byte(list), 0644))
+
+	cfg := config.New(
+		config.OptCacheDir(filepath.Join(dir, "cache")),
+		config.OptCode(nomcode.Zoological),
+		config.OptInferGenera(infer),
+	)
+	n := names.New(cfg)
+	arc := sourcetest.Harvest(t, n, path)
+	assert.Equal(map[string]int{"duplicate name": 1}, n.Report().Rejected)
+	return arc
+}
+
+func TestExtractSkipDownload(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "owls.txt")
+	assert.Nil(os.WriteFile(path, []byte(list), 0644))
+
+	cfg := config.New(config.OptCacheDir(filepath.Join(dir, "cache")))
+	ctx := context.Background()
+	assert.Nil(names.New(cfg).Extract(ctx, path))
+
+	// an empty path (skip download mode) uses the file copied before.
+	arc := sourcetest.Harvest(t, names.New(cfg), "")
+	defer arc.Close()
+
+	var count int
+	err := arc.Db().QueryRow("SELECT count(*) FROM name").Scan(&count)
+	assert.Nil(err)
+	assert.Equal(5, count)
+}
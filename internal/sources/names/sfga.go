@@ -0,0 +1,28 @@
+package names
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sfborg/sflib/pkg/sfga"
+)
+
+// ToSfga imports the names file into a SFGA archive.
+func (n *names) ToSfga(ctx context.Context, sfga sfga.Archive) error {
+	var err error
+	n.sfga = sfga
+
+	slog.Info("importing Meta")
+	n.cfg.Info("Importing Meta")
+	if err = n.importMeta(); err != nil {
+		return err
+	}
+
+	slog.Info("importing Name Usages")
+	n.cfg.Info("Importing Name Usages")
+	if err = n.importNameUsages(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
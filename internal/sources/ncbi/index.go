@@ -0,0 +1,326 @@
+package ncbi
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sfborg/harvester/pkg/event"
+	_ "modernc.org/sqlite"
+)
+
+const indexSchema = `
+CREATE TABLE names (taxid TEXT, name TEXT, class TEXT);
+CREATE TABLE merged (old_id TEXT PRIMARY KEY, new_id TEXT);
+CREATE TABLE deleted (taxid TEXT PRIMARY KEY);
+CREATE TABLE types (taxid TEXT, status TEXT, identifier TEXT);
+CREATE TABLE hosts (taxid TEXT, host TEXT);
+CREATE TABLE lineages (
+  taxid TEXT PRIMARY KEY, species TEXT, genus TEXT, family TEXT, ord TEXT,
+  class TEXT, phylum TEXT, kingdom TEXT
+);
+`
+
+const indexIndices = `
+CREATE INDEX names_taxid ON names (taxid);
+CREATE INDEX merged_new_id ON merged (new_id);
+CREATE INDEX types_taxid ON types (taxid);
+CREATE INDEX hosts_taxid ON hosts (taxid);
+`
+
+// buildIndex imports names and other data of nodes from dmp files to a
+// temporary SQLite database, so they can be joined with nodes without
+// keeping the whole taxonomy in memory. Only names.dmp is required.
+// Small tables of divisions and genetic codes are kept in memory.
+func (n *ncbi) buildIndex(ctx context.Context) error {
+	slog.Info("importing NCBI data to a temporary SQLite database")
+	n.cfg.Info("Importing NCBI data to a temporary SQLite database")
+	if err := n.openIndex(); err != nil {
+		return err
+	}
+
+	dir := n.cfg.ExtractDir
+	namesPath := filepath.Join(dir, "names.dmp")
+	if _, err := os.Stat(namesPath); err != nil {
+		return err
+	}
+	_, err := n.importDmp(ctx, namesPath, 4,
+		"INSERT INTO names (taxid, name, class) VALUES (?, ?, ?)",
+		func(fields []string) [][]any {
+			return [][]any{{fields[0], fields[1], fields[3]}}
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if err = n.importOldIDs(ctx); err != nil {
+		return err
+	}
+
+	_, err = n.importDmp(ctx, filepath.Join(dir, "typematerial.dmp"), 4,
+		"INSERT INTO types (taxid, status, identifier) VALUES (?, ?, ?)",
+		func(fields []string) [][]any {
+			return [][]any{{fields[0], fields[2], fields[3]}}
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = n.importDmp(ctx, filepath.Join(dir, "host.dmp"), 2,
+		"INSERT INTO hosts (taxid, host) VALUES (?, ?)",
+		func(fields []string) [][]any {
+			var res [][]any
+			for _, v := range strings.Split(fields[1], ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					res = append(res, []any{fields[0], v})
+				}
+			}
+			return res
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = n.importDmp(ctx, filepath.Join(dir, "rankedlineage.dmp"), 9,
+		`INSERT INTO lineages
+  (taxid, species, genus, family, ord, class, phylum, kingdom)
+  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		func(fields []string) [][]any {
+			row := make([]any, 8)
+			row[0] = fields[0]
+			for i, v := range fields[2:9] {
+				row[i+1] = v
+			}
+			return [][]any{row}
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err = n.db.ExecContext(ctx, indexIndices); err != nil {
+		return err
+	}
+	return n.readCodes(ctx)
+}
+
+// openIndex creates a new temporary database.
+func (n *ncbi) openIndex() error {
+	if err := os.Remove(n.dbPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	db, err := sql.Open("sqlite", n.dbPath)
+	if err != nil {
+		return err
+	}
+	n.db = db
+
+	_, err = db.Exec(`
+PRAGMA temp_store = MEMORY;
+PRAGMA journal_mode = OFF;
+PRAGMA synchronous = OFF;
+` + indexSchema)
+	if err != nil {
+		n.closeIndex()
+		return err
+	}
+	return nil
+}
+
+// closeIndex closes and removes the temporary database, if it is open.
+func (n *ncbi) closeIndex() {
+	if n.db == nil {
+		return
+	}
+	if err := n.db.Close(); err != nil {
+		slog.Warn("cannot close NCBI database", "error", err)
+	}
+	n.db = nil
+	if err := os.Remove(n.dbPath); err != nil {
+		slog.Warn("cannot remove NCBI database", "error", err)
+	}
+}
+
+// importOldIDs imports taxids that were merged into other nodes from
+// merged.dmp and deleted taxids from delnodes.dmp. Merged taxids become
+// alternative IDs of the nodes they were merged into, so they can be
+// resolved to current taxids. Deleted taxids are counted in the report.
+func (n *ncbi) importOldIDs(ctx context.Context) error {
+	dir := n.cfg.ExtractDir
+	mergedNum, err := n.importDmp(ctx, filepath.Join(dir, "merged.dmp"), 2,
+		"INSERT OR REPLACE INTO merged (old_id, new_id) VALUES (?, ?)",
+		func(fields []string) [][]any {
+			return [][]any{{fields[0], fields[1]}}
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	deletedNum, err := n.importDmp(ctx, filepath.Join(dir, "delnodes.dmp"), 1,
+		"INSERT OR IGNORE INTO deleted (taxid) VALUES (?)",
+		func(fields []string) [][]any {
+			return [][]any{{fields[0]}}
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	// taxids can be merged several times, the number of steps is limited
+	// in case of cycles.
+	q := `
+UPDATE merged
+  SET new_id = (SELECT m.new_id FROM merged m WHERE m.old_id = merged.new_id)
+  WHERE new_id IN (SELECT old_id FROM merged)`
+	for range 100 {
+		res, err := n.db.ExecContext(ctx, q)
+		if err != nil {
+			return err
+		}
+		if num, _ := res.RowsAffected(); num == 0 {
+			break
+		}
+	}
+
+	q = "DELETE FROM merged WHERE new_id IN (SELECT taxid FROM deleted)"
+	if _, err = n.db.ExecContext(ctx, q); err != nil {
+		return err
+	}
+
+	n.Report().AddRejected("deleted taxid", deletedNum)
+	slog.Info("collected old taxids",
+		"merged", mergedNum, "deleted", deletedNum)
+	return nil
+}
+
+// readCodes reads names of divisions from division.dmp and names of
+// genetic codes from gencode.dmp.
+func (n *ncbi) readCodes(ctx context.Context) error {
+	dir := n.cfg.ExtractDir
+	n.divisions = make(map[string]division)
+	err := readDmp(ctx, filepath.Join(dir, "division.dmp"), 3,
+		func(fields []string) error {
+			n.divisions[fields[0]] = division{code: fields[1], name: fields[2]}
+			return nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	n.gencodes = make(map[string]string)
+	return readDmp(ctx, filepath.Join(dir, "gencode.dmp"), 3,
+		func(fields []string) error {
+			n.gencodes[fields[0]] = fields[2]
+			return nil
+		},
+	)
+}
+
+// importDmp inserts rows of a dmp file to the temporary database in one
+// transaction. The rows function converts fields of a line to arguments
+// of the query. It returns the number of read lines.
+func (n *ncbi) importDmp(
+	ctx context.Context,
+	path string,
+	fieldsNum int,
+	q string,
+	rows func(fields []string) [][]any,
+) (int, error) {
+	tx, err := n.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	file := filepath.Base(path)
+	var count int
+	err = readDmp(ctx, path, fieldsNum, func(fields []string) error {
+		count++
+		if count%100_000 == 0 {
+			n.cfg.Notify(event.Event{
+				Type:  event.Processed,
+				Count: int64(count),
+				Unit:  "rows of " + file,
+			})
+		}
+		for _, v := range rows(fields) {
+			if _, err := stmt.ExecContext(ctx, v...); err != nil {
+				return fmt.Errorf("cannot import %s: %w", file, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	if count > 0 {
+		n.cfg.Notify(event.Event{
+			Type:  event.Processed,
+			Count: int64(count),
+			Unit:  "rows of " + file,
+			Done:  true,
+		})
+		slog.Info("imported data", "file", file, "rows", count)
+	}
+	return count, nil
+}
+
+// readDmp calls f for every row of a dmp file that has at least fieldsNum
+// fields. It does nothing if the file does not exist.
+func readDmp(
+	ctx context.Context,
+	path string,
+	fieldsNum int,
+	f func(fields []string) error,
+) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		slog.Info("file is missing, skipping", "file", path)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		fields := splitDmp(scanner.Text())
+		if len(fields) < fieldsNum {
+			return fmt.Errorf(
+				"wrong number of fields in %s: %s", path, scanner.Text(),
+			)
+		}
+		if err = f(fields); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func splitDmp(line string) []string {
+	line = strings.TrimSuffix(line, "\t|")
+	return strings.Split(line, "\t|\t")
+}
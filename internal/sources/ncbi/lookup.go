@@ -0,0 +1,134 @@
+package ncbi
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+)
+
+// nodesData contains names and other data of a batch of nodes by their
+// taxids.
+type nodesData struct {
+	names    map[string][]name
+	oldIDs   map[string][]string
+	types    map[string][]typeMaterial
+	hosts    map[string][]string
+	lineages map[string]lineage
+}
+
+// lookup loads data of nodes with given taxids from the temporary
+// database. Records keep the order they had in dmp files.
+func (n *ncbi) lookup(ctx context.Context, ids []string) (*nodesData, error) {
+	js, err := json.Marshal(ids)
+	if err != nil {
+		return nil, err
+	}
+	res := nodesData{
+		names:    make(map[string][]name),
+		oldIDs:   make(map[string][]string),
+		types:    make(map[string][]typeMaterial),
+		hosts:    make(map[string][]string),
+		lineages: make(map[string]lineage),
+	}
+
+	q := `
+SELECT taxid, name, class FROM names
+  WHERE taxid IN (SELECT value FROM json_each(?)) ORDER BY rowid`
+	err = n.query(ctx, q, js, func(rows *sql.Rows) error {
+		var id string
+		var v name
+		if err := rows.Scan(&id, &v.value, &v.class); err != nil {
+			return err
+		}
+		res.names[id] = append(res.names[id], v)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	q = `
+SELECT new_id, old_id FROM merged
+  WHERE new_id IN (SELECT value FROM json_each(?)) ORDER BY old_id`
+	err = n.query(ctx, q, js, func(rows *sql.Rows) error {
+		var id, oldID string
+		if err := rows.Scan(&id, &oldID); err != nil {
+			return err
+		}
+		res.oldIDs[id] = append(res.oldIDs[id], oldID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	q = `
+SELECT taxid, status, identifier FROM types
+  WHERE taxid IN (SELECT value FROM json_each(?)) ORDER BY rowid`
+	err = n.query(ctx, q, js, func(rows *sql.Rows) error {
+		var id string
+		var v typeMaterial
+		if err := rows.Scan(&id, &v.status, &v.identifier); err != nil {
+			return err
+		}
+		res.types[id] = append(res.types[id], v)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	q = `
+SELECT taxid, host FROM hosts
+  WHERE taxid IN (SELECT value FROM json_each(?)) ORDER BY rowid`
+	err = n.query(ctx, q, js, func(rows *sql.Rows) error {
+		var id, host string
+		if err := rows.Scan(&id, &host); err != nil {
+			return err
+		}
+		res.hosts[id] = append(res.hosts[id], host)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	q = `
+SELECT taxid, species, genus, family, ord, class, phylum, kingdom
+  FROM lineages WHERE taxid IN (SELECT value FROM json_each(?))`
+	err = n.query(ctx, q, js, func(rows *sql.Rows) error {
+		var id string
+		var l lineage
+		err := rows.Scan(&id, &l[0], &l[1], &l[2], &l[3], &l[4], &l[5], &l[6])
+		if err != nil {
+			return err
+		}
+		res.lineages[id] = l
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// query calls f for every row returned by the query for taxids in JSON.
+func (n *ncbi) query(
+	ctx context.Context,
+	q string,
+	ids []byte,
+	f func(rows *sql.Rows) error,
+) error {
+	rows, err := n.db.QueryContext(ctx, q, string(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = f(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -1,54 +1,137 @@
 package ncbi
 
 import (
+	"database/sql"
 	"path/filepath"
 
-	"github.com/sfborg/harvester/internal/base"
+	"github.com/gnames/gnparser"
+	"github.com/sfborg/harvester/pkg/base"
 	"github.com/sfborg/harvester/pkg/config"
 	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/sflib/pkg/coldp"
 	"github.com/sfborg/sflib/pkg/sfga"
 )
 
 type ncbi struct {
 	data.Convertor
-	cfg                config.Config
-	sfga               sfga.Archive
-	namePath, nodePath string
-	names              map[string]map[string]string
-	data               []datum
+	cfg  config.Config
+	sfga sfga.Archive
+
+	// db is a temporary index of names and other data of nodes, that is
+	// queried for every batch of nodes.
+	db        *sql.DB
+	dbPath    string
+	nodePath  string
+	divisions map[string]division
+	gencodes  map[string]string
+
+	// gnp parses batches of names concurrently.
+	gnp gnparser.GNparser
+}
+
+// name is a record of names.dmp with its name class.
+type name struct {
+	value string
+	class string
 }
 
 type synonym struct {
-	name            string
-	taxonomicStatus string
+	name       string
+	nameString string
+	class      string
+}
+
+// typeMaterial is a type strain or specimen of a taxon.
+type typeMaterial struct {
+	status     string
+	identifier string
+}
+
+// lineage contains names of species, genus, family, order, class, phylum
+// and kingdom of a taxon from rankedlineage.dmp.
+type lineage [7]string
+
+type division struct {
+	code string
+	name string
 }
 
 type datum struct {
-	taxonID   string
-	parentID  string
-	canonical string
-	nameStr   string
-	rank      string
-	vernNames []string
-	synonyms  []synonym
+	taxonID      string
+	parentID     string
+	canonical    string
+	nameStr      string
+	rank         string
+	divisionID   string
+	gencodeID    string
+	mitoCodeID   string
+	oldIDs       []string
+	vernNames    []name
+	synonyms     []synonym
+	typeMaterial []typeMaterial
+	lineage      lineage
+	hosts        []string
+
+	// skipped are names of classes that are not imported.
+	skipped []name
 }
 
-var vernType = []string{"common name", "genbank common name"}
-var nameType = []string{"valid", "authority"}
+// vernClasses are classes of names.dmp that become vernacular names, with
+// their languages. Blast names are common names of large groups, for
+// example "birds", acronyms are not in any particular language.
+var vernClasses = map[string]string{
+	"genbank common name": "eng",
+	"common name":         "eng",
+	"blast name":          "eng",
+	"genbank acronym":     "",
+	"acronym":             "",
+}
+
+// synonymClasses are classes of names.dmp that become synonyms. Names of
+// "includes" class are names of taxa merged into the node, "in-part" names
+// are shared by several nodes. Misspellings also get a name relation from
+// nameRelations.
+var synonymClasses = map[string]coldp.TaxonomicStatus{
+	"synonym":          coldp.SynonymTS,
+	"genbank synonym":  coldp.SynonymTS,
+	"equivalent name":  coldp.SynonymTS,
+	"includes":         coldp.SynonymTS,
+	"misspelling":      coldp.SynonymTS,
+	"anamorph":         coldp.SynonymTS,
+	"genbank anamorph": coldp.SynonymTS,
+	"teleomorph":       coldp.SynonymTS,
+	"authority":        coldp.SynonymTS,
+	"in-part":          coldp.AmbiguousSynonymTS,
+}
+
+// nameRelations are relations of the scientific name of a node to its
+// synonyms of given classes.
+var nameRelations = map[string]coldp.NomRelType{
+	"misspelling": coldp.SpellingCorrection,
+}
+
+const (
+	taxdumpURL    = "https://ftp.ncbi.nlm.nih.gov/pub/taxonomy/taxdump.tar.gz"
+	newTaxdumpURL = "https://ftp.ncbi.nlm.nih.gov/pub/taxonomy/new_taxdump/" +
+		"new_taxdump.tar.gz"
+)
 
 func New(cfg config.Config) data.Convertor {
 	set := data.DataSet{
 		Label:       "ncbi",
 		Name:        "National Center for Biotechnology Information",
 		ManualSteps: false,
-		URL:         "https://ftp.ncbi.nlm.nih.gov/pub/taxonomy/taxdump.tar.gz",
+		URL:         taxdumpURL,
+	}
+	if cfg.NewTaxdump {
+		set.URL = newTaxdumpURL
 	}
+	cfg = cfg.ForLabel(set.Label)
 	res := ncbi{
 		cfg:       cfg,
 		Convertor: base.New(cfg, &set),
-		namePath:  filepath.Join(cfg.ExtractDir, "names.dmp"),
+		dbPath:    filepath.Join(cfg.ExtractDir, "ncbi.sqlite"),
 		nodePath:  filepath.Join(cfg.ExtractDir, "nodes.dmp"),
-		names:     make(map[string]map[string]string),
 	}
 	return &res
 }
@@ -0,0 +1,291 @@
+package ncbi_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sfborg/harvester/internal/sources/ncbi"
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/sflib/pkg/sfga"
+	"github.com/stretchr/testify/assert"
+)
+
+var names = [][]string{
+	{"1", "root", "", "scientific name"},
+	{"9605", "Homo", "", "scientific name"},
+	{"9605", "Homo Linnaeus, 1758", "", "authority"},
+	{"9605", "humans", "", "blast name"},
+	{"9606", "Homo sapiens", "", "scientific name"},
+	{"9606", "Homo sapiens Linnaeus, 1758", "", "authority"},
+	{"9606", "human", "", "genbank common name"},
+	{"9606", "man", "", "common name"},
+	{"9606", "Homo sapiens neanderthalensis", "", "includes"},
+	{"9606", "Homo sapiens King, 1864", "", "authority"},
+	{"9606", "Homo sapeins", "", "misspelling"},
+	{"1280", "Staphylococcus aureus", "", "scientific name"},
+	{"1280", "ATCC 12600", "", "type material"},
+	{"1280", "Micrococcus aureus", "", "synonym"},
+	{"1280", "Micrococcus aureus (Rosenbach 1884) Zopf 1885", "", "authority"},
+	{"1280", "Staphylococcus pyogenes", "", "in-part"},
+	{"1280", "SA", "", "acronym"},
+	{"1280", "Staphylococcus aurea", "", "unpublished name"},
+	{"1279", "Staphylococcus", "", "scientific name"},
+	{"1279", "Staphylococcus pyogenes", "", "in-part"},
+}
+
+// nodes are converted in batches of 2, the parent of 9606 is in the
+// second batch.
+var nodes = [][]string{
+	{"1", "1", "no rank"},
+	{"9606", "9605", "species"},
+	{"1280", "1", "species"},
+	{"9605", "1", "genus"},
+	{"1279", "1", "genus"},
+}
+
+var merged = [][]string{
+	{"63221", "9606"},
+	{"10", "63221"},
+}
+
+var deleted = [][]string{{"3"}, {"4"}}
+
+func TestNCBI(t *testing.T) {
+	assert := assert.New(t)
+	nodeRows := make([][]string, len(nodes))
+	for i, v := range nodes {
+		nodeRows[i] = append(v, make([]string, 10)...)
+	}
+	n, arc := harvest(t, map[string][][]string{
+		"names.dmp":    names,
+		"nodes.dmp":    nodeRows,
+		"merged.dmp":   merged,
+		"delnodes.dmp": deleted,
+	})
+	defer arc.Close()
+	rejected := n.Report().Rejected
+	assert.Equal(2, rejected["deleted taxid"])
+	assert.Equal(1, rejected["not imported name class: unpublished name"])
+	db := arc.Db()
+
+	taxa := query(t, db, "SELECT col__id, col__parent_id FROM taxon")
+	assert.ElementsMatch([]string{
+		"9606|9605", "1280|1280", "9605|9605", "1279|1279",
+	}, taxa)
+
+	var altID string
+	err := db.QueryRow(
+		"SELECT col__alternative_id FROM taxon WHERE col__id = '9606'",
+	).Scan(&altID)
+	assert.Nil(err)
+	assert.Equal("ncbi:10,ncbi:63221", altID)
+
+	var name string
+	err = db.QueryRow(
+		"SELECT gn__scientific_name_string FROM name WHERE col__id = '9606'",
+	).Scan(&name)
+	assert.Nil(err)
+	assert.Equal("Homo sapiens Linnaeus, 1758", name)
+
+	syns := query(t, db, `
+SELECT n.gn__scientific_name_string, s.col__status_id, n.col__remarks
+  FROM synonym s JOIN name n ON n.col__id = s.col__name_id
+  ORDER BY n.gn__scientific_name_string`)
+	assert.Equal([]string{
+		"Homo sapeins|SYNONYM|NCBI name class: misspelling",
+		"Homo sapiens King, 1864|SYNONYM|NCBI name class: authority",
+		"Homo sapiens neanderthalensis|SYNONYM|NCBI name class: includes",
+		"Micrococcus aureus (Rosenbach 1884) Zopf 1885|SYNONYM|",
+		"Staphylococcus pyogenes|AMBIGUOUS_SYNONYM|NCBI name class: in-part",
+		"Staphylococcus pyogenes|AMBIGUOUS_SYNONYM|NCBI name class: in-part",
+	}, syns)
+
+	verns := query(t, db, `
+SELECT col__name, col__preferred FROM vernacular
+  WHERE col__taxon_id = '9606' ORDER BY col__name`)
+	assert.Equal([]string{"human|1", "man|0"}, verns)
+
+	verns = query(t, db, `
+SELECT col__taxon_id, col__name, col__language, col__remarks FROM vernacular
+  WHERE col__remarks != '' ORDER BY col__taxon_id`)
+	assert.Equal([]string{
+		"1280|SA||NCBI name class: acronym",
+		"9605|humans|eng|NCBI name class: blast name",
+	}, verns)
+
+	rels := query(t, db, `
+SELECT r.col__name_id, n.gn__scientific_name_string, r.col__type_id
+  FROM name_relation r JOIN name n ON n.col__id = r.col__related_name_id`)
+	assert.Equal([]string{"9606|Homo sapeins|SPELLING_CORRECTION"}, rels)
+
+	var citation string
+	err = db.QueryRow(
+		"SELECT col__citation FROM type_material WHERE col__name_id = '1280'",
+	).Scan(&citation)
+	assert.Nil(err)
+	assert.Equal("ATCC 12600", citation)
+}
+
+// TestSynonymIDs checks that the same synonym of different nodes gets
+// different IDs, and that IDs do not change between harvests.
+func TestSynonymIDs(t *testing.T) {
+	assert := assert.New(t)
+	nodeRows := make([][]string, len(nodes))
+	for i, v := range nodes {
+		nodeRows[i] = append(v, make([]string, 10)...)
+	}
+	files := map[string][][]string{"names.dmp": names, "nodes.dmp": nodeRows}
+
+	var ids [2][]string
+	for i := range ids {
+		_, arc := harvest(t, files)
+		ids[i] = query(t, arc.Db(), "SELECT col__id FROM name ORDER BY col__id")
+		arc.Close()
+	}
+	assert.Len(ids[0], 10)
+	assert.Equal(ids[0], ids[1])
+
+	seen := make(map[string]struct{})
+	for _, v := range ids[0] {
+		seen[v] = struct{}{}
+	}
+	assert.Len(seen, len(ids[0]), "IDs are unique")
+}
+
+func TestNewTaxdump(t *testing.T) {
+	assert := assert.New(t)
+	// nodes of new_taxdump have 18 fields, division, genetic code and
+	// mitochondrial genetic code are at 4, 6 and 8.
+	nodeRows := make([][]string, len(nodes))
+	for i, v := range nodes {
+		nodeRows[i] = append(v, make([]string, 15)...)
+		if v[0] == "1280" {
+			nodeRows[i][4], nodeRows[i][6], nodeRows[i][8] = "0", "11", "0"
+		}
+	}
+	_, arc := harvest(t, map[string][][]string{
+		"names.dmp": names,
+		"nodes.dmp": nodeRows,
+		"division.dmp": {
+			{"0", "BCT", "Bacteria", ""},
+		},
+		"gencode.dmp": {
+			{"0", "", "Unspecified", "", ""},
+			{"11", "", "Bacterial, Archaeal and Plant Plastid", "", ""},
+		},
+		"typematerial.dmp": {
+			{"1280", "Staphylococcus aureus", "type strain", "ATCC 12600"},
+			{"1280", "Staphylococcus aureus", "type strain", "DSM 20231"},
+			{"9606", "Homo sapiens", "neotype", "NHMUK 123"},
+		},
+		"host.dmp": {
+			{"1280", "human,vertebrates"},
+		},
+		"rankedlineage.dmp": {
+			{
+				"1280", "Staphylococcus aureus", "", "Staphylococcus",
+				"Staphylococcaceae", "Bacillales", "Bacilli", "Bacillota",
+				"Bacillati", "Bacteria",
+			},
+		},
+	})
+	defer arc.Close()
+	db := arc.Db()
+
+	var family, kingdom string
+	err := db.QueryRow(`
+SELECT col__family, col__kingdom FROM taxon WHERE col__id = '1280'`,
+	).Scan(&family, &kingdom)
+	assert.Nil(err)
+	assert.Equal("Staphylococcaceae", family)
+	assert.Equal("Bacillati", kingdom)
+
+	tms := query(t, db, `
+SELECT col__name_id, col__citation, col__status_id, col__remarks
+  FROM type_material ORDER BY col__name_id, col__citation`)
+	assert.Equal([]string{
+		"1280|ATCC 12600||type strain",
+		"1280|DSM 20231||type strain",
+		"9606|NHMUK 123|NEOTYPE|",
+	}, tms)
+
+	props := query(t, db, `
+SELECT col__property, col__value, col__remarks
+  FROM taxon_property WHERE col__taxon_id = '1280' ORDER BY col__property`)
+	assert.Equal([]string{
+		"division|Bacteria|BCT",
+		"genetic code|11|Bacterial, Archaeal and Plant Plastid",
+	}, props)
+
+	hosts := query(t, db, `
+SELECT col__taxon_id, col__related_taxon_scientific_name, col__type_id
+  FROM species_interaction ORDER BY col__related_taxon_scientific_name`)
+	assert.Equal([]string{
+		"1280|human|HAS_HOST",
+		"1280|vertebrates|HAS_HOST",
+	}, hosts)
+}
+
+func harvest(
+	t *testing.T,
+	files map[string][][]string,
+) (data.Convertor, sfga.Archive) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	cfg := config.New(
+		config.OptCacheDir(filepath.Join(dir, "cache")),
+		config.OptBatchSize(2),
+		config.OptParserJobsNum(3),
+	)
+	extractDir := cfg.ForLabel("ncbi").ExtractDir
+	assert.Nil(os.MkdirAll(extractDir, 0755))
+	for k, v := range files {
+		path := filepath.Join(extractDir, k)
+		assert.Nil(os.WriteFile(path, []byte(dmp(v)), 0644))
+	}
+
+	n := ncbi.New(cfg)
+	ctx := context.Background()
+	arc, err := n.InitSfga(ctx)
+	assert.Nil(err)
+	assert.Nil(n.ToSfga(ctx, arc))
+	_, err = os.Stat(filepath.Join(extractDir, "ncbi.sqlite"))
+	assert.True(os.IsNotExist(err), "temporary database is removed")
+	return n, arc
+}
+
+// query returns rows of the query with fields separated by '|'.
+func query(t *testing.T, db *sql.DB, q string) []string {
+	assert := assert.New(t)
+	rows, err := db.Query(q)
+	assert.Nil(err)
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	assert.Nil(err)
+	var res []string
+	for rows.Next() {
+		fields := make([]string, len(cols))
+		ptrs := make([]any, len(cols))
+		for i := range fields {
+			ptrs[i] = &fields[i]
+		}
+		assert.Nil(rows.Scan(ptrs...))
+		res = append(res, strings.Join(fields, "|"))
+	}
+	assert.Nil(rows.Err())
+	return res
+}
+
+func dmp(rows [][]string) string {
+	var res strings.Builder
+	for _, v := range rows {
+		res.WriteString(strings.Join(v, "\t|\t") + "\t|\n")
+	}
+	return res.String()
+}
@@ -2,53 +2,58 @@ package ncbi
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
-	"slices"
 	"strings"
 
+	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/harvester/pkg/event"
 	"golang.org/x/sync/errgroup"
 )
 
-func (n *ncbi) collectNodes() error {
-	var g errgroup.Group
-	var err error
+// convertNodes reads nodes.dmp and converts nodes to SFGA in batches.
+func (n *ncbi) convertNodes(ctx context.Context) error {
+	g, ctx := errgroup.WithContext(ctx)
 	chIn := make(chan []string)
 
 	g.Go(func() error {
-		return n.processNodes(chIn)
+		return n.processNodes(ctx, chIn)
 	})
 
 	g.Go(func() error {
 		defer close(chIn)
-		err = n.loadNodes(chIn)
-		return err
+		return n.loadNodes(ctx, chIn)
 	})
 
-	if err = g.Wait(); err != nil {
-		return err
-	}
-	return nil
+	return g.Wait()
 }
 
-func (n *ncbi) loadNodes(chIn chan<- []string) error {
+func (n *ncbi) loadNodes(ctx context.Context, chIn chan<- []string) error {
 	file, err := os.Open(n.nodePath)
 	if err != nil {
-		panic(err)
+		return err
 	}
 	defer file.Close()
 
+	smp := data.NewSampler(n.cfg)
 	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		line = strings.TrimSuffix(line, "\t|")
-		fields := strings.Split(line, "\t|\t")
-		if len(fields) != 13 {
+	for !smp.Done() && scanner.Scan() {
+		fields := splitDmp(scanner.Text())
+		// new_taxdump adds fields to the 13 fields of taxdump.
+		if len(fields) < 13 {
 			return fmt.Errorf("wrong number of nodes fields: %d, %s",
-				len(fields), line,
+				len(fields), scanner.Text(),
 			)
 		}
-		chIn <- fields
+		if !smp.Keep() {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case chIn <- fields:
+		}
 	}
 	if err := scanner.Err(); err != nil {
 		return err
@@ -56,57 +61,177 @@ func (n *ncbi) loadNodes(chIn chan<- []string) error {
 	return nil
 }
 
-func (n *ncbi) processNodes(chIn <-chan []string) error {
+// processNodes collects nodes in batches of BatchSize, joins them with
+// their names and other data from the temporary database and saves them
+// to SFGA.
+func (n *ncbi) processNodes(ctx context.Context, chIn <-chan []string) error {
+	var total int
+	batch := make([][]string, 0, n.cfg.BatchSize)
 	for row := range chIn {
-		id := row[0]
-		if id == "1" {
+		if row[0] == "1" {
 			continue
 		}
-
-		parentID := row[1]
-		rank := row[2]
-		if rank == "no rank" {
-			rank = ""
+		batch = append(batch, row)
+		if len(batch) < n.cfg.BatchSize {
+			continue
 		}
-		if parentID == "1" {
-			parentID = id
+		total += len(batch)
+		if err := n.processBatch(ctx, batch, total); err != nil {
+			return err
 		}
-		if _, ok := n.names[id]; !ok {
-			continue
+		batch = batch[:0]
+	}
+
+	total += len(batch)
+	if len(batch) > 0 {
+		if err := n.processBatch(ctx, batch, total); err != nil {
+			return err
 		}
-		if _, ok := n.names[id]["valid"]; !ok {
-			continue
+	}
+	n.cfg.Notify(event.Event{
+		Type: event.Processed, Count: int64(total), Unit: "nodes", Done: true,
+	})
+	return nil
+}
+
+func (n *ncbi) processBatch(
+	ctx context.Context,
+	rows [][]string,
+	total int,
+) error {
+	ids := make([]string, len(rows))
+	for i, v := range rows {
+		ids[i] = v[0]
+	}
+	nd, err := n.lookup(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	rep := n.Report()
+	ds := make([]datum, 0, len(rows))
+	for _, v := range rows {
+		d, ok := newDatum(v, nd)
+		for _, skip := range d.skipped {
+			rep.Reject(data.Rejected{
+				Location: "taxid " + d.taxonID,
+				Reason:   "not imported name class: " + skip.class,
+				Raw:      skip.value,
+			})
+		}
+		if ok {
+			ds = append(ds, d)
 		}
+	}
+	if err = n.insertBatch(ctx, ds); err != nil {
+		return err
+	}
+	n.cfg.Notify(event.Event{
+		Type: event.Processed, Count: int64(total), Unit: "nodes",
+	})
+	return nil
+}
 
-		var vernNames []string
-		var synonyms []synonym
-		for k := range n.names[id] {
-			if slices.Contains(nameType, k) {
+// newDatum creates a datum out of a row of nodes.dmp and data of the node.
+// It returns false if the node has no scientific name. Names of unknown
+// classes are kept in skipped.
+func newDatum(row []string, nd *nodesData) (datum, bool) {
+	id := row[0]
+	parentID := row[1]
+	rank := row[2]
+	if rank == "no rank" {
+		rank = ""
+	}
+	if parentID == "1" {
+		parentID = id
+	}
+
+	rec := datum{
+		taxonID:      id,
+		parentID:     parentID,
+		rank:         rank,
+		divisionID:   row[4],
+		gencodeID:    row[6],
+		mitoCodeID:   row[8],
+		oldIDs:       nd.oldIDs[id],
+		typeMaterial: nd.types[id],
+		lineage:      nd.lineages[id],
+		hosts:        nd.hosts[id],
+	}
+	// typematerial.dmp of new_taxdump has more details than names.
+	hasTypes := len(rec.typeMaterial) > 0
+	var auths []string
+	seen := make(map[string]struct{})
+	for _, v := range nd.names[id] {
+		switch {
+		case v.class == "scientific name":
+			rec.canonical = v.value
+		case v.class == "authority":
+			auths = append(auths, v.value)
+		case v.class == "type material":
+			if !hasTypes {
+				rec.typeMaterial = append(rec.typeMaterial,
+					typeMaterial{identifier: v.value})
+			}
+		case hasClass(vernClasses, v.class):
+			rec.vernNames = append(rec.vernNames, v)
+		default:
+			if !hasClass(synonymClasses, v.class) {
+				rec.skipped = append(rec.skipped, v)
 				continue
 			}
-			name := n.names[id][k]
-			if slices.Contains(vernType, k) {
-				vernNames = append(vernNames, name)
-			} else if k == "synonym" {
-				synonyms = append(synonyms, synonym{name: name, taxonomicStatus: k})
+			if _, ok := seen[v.value]; ok {
+				continue
 			}
+			seen[v.value] = struct{}{}
+			rec.synonyms = append(rec.synonyms, synonym{
+				name: v.value, class: v.class,
+			})
 		}
-		nameString := n.names[id]["valid"]
-		canonical := nameString
-		if au, ok := n.names[id]["authority"]; ok {
-			nameString = au
+	}
+	if rec.canonical == "" {
+		return rec, false
+	}
+	rec.nameStr = rec.canonical
+	addAuthorities(&rec, auths)
+	return rec, true
+}
+
+// addAuthorities sets names with authorship to the scientific name and
+// synonyms they belong to. Other authorities, including variants of the
+// authorship of the scientific name, become synonyms.
+func addAuthorities(d *datum, auths []string) {
+	for _, au := range auths {
+		if d.nameStr == d.canonical && hasName(au, d.canonical) {
+			d.nameStr = au
+			continue
 		}
-		rec := datum{
-			taxonID:   id,
-			parentID:  parentID,
-			canonical: canonical,
-			nameStr:   nameString,
-			rank:      rank,
-			vernNames: vernNames,
-			synonyms:  synonyms,
+		if au == d.nameStr {
+			continue
 		}
-		n.data = append(n.data, rec)
 
+		var found bool
+		for i := range d.synonyms {
+			syn := &d.synonyms[i]
+			if syn.nameString == "" && hasName(au, syn.name) {
+				syn.nameString = au
+				found = true
+				break
+			}
+		}
+		if !found {
+			d.synonyms = append(d.synonyms, synonym{
+				name: au, nameString: au, class: "authority",
+			})
+		}
 	}
-	return nil
+}
+
+func hasClass[T any](classes map[string]T, class string) bool {
+	_, ok := classes[class]
+	return ok
+}
+
+func hasName(authority, name string) bool {
+	return authority == name || strings.HasPrefix(authority, name+" ")
 }
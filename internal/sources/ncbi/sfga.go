@@ -1,48 +1,37 @@
 package ncbi
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"log/slog"
+	"strings"
 
-	"github.com/gnames/gnparser"
-	"github.com/gnames/gnuuid"
 	"github.com/sfborg/harvester/pkg/data"
 	"github.com/sfborg/sflib/pkg/coldp"
 	"github.com/sfborg/sflib/pkg/sfga"
 )
 
-func (n *ncbi) ToSfga(sfga sfga.Archive) error {
+// ToSfga converts NCBI taxonomy to SFGA. Names and other data are imported
+// to a temporary database first, then nodes are converted in batches, so
+// memory use does not depend on the size of the taxonomy.
+func (n *ncbi) ToSfga(ctx context.Context, sfga sfga.Archive) error {
 	var err error
 	n.sfga = sfga
-	err = n.collectNames()
-	if err != nil {
-		return err
-	}
-	err = n.collectNodes()
-	if err != nil {
-		return err
-	}
+	defer n.closeIndex()
 
-	err = n.populateSfga()
-	if err != nil {
+	if err = n.setMetadata(); err != nil {
 		return err
 	}
-
-	return nil
-}
-
-func (n *ncbi) populateSfga() error {
-	var err error
-	err = n.setMetadata()
-	if err != nil {
+	if err = n.buildIndex(ctx); err != nil {
 		return err
 	}
 
-	err = n.setNameUsage()
-	if err != nil {
-		return err
-	}
+	n.gnp = data.NewParser(n.cfg)
 
-	return nil
+	slog.Info("converting NCBI nodes")
+	n.cfg.Info("Converting NCBI nodes")
+	return n.convertNodes(ctx)
 }
 
 func (n *ncbi) setMetadata() error {
@@ -64,72 +53,252 @@ func (n *ncbi) setMetadata() error {
 	return nil
 }
 
-func (n *ncbi) setNameUsage() error {
-	var rejectedNum, rejectedSyn int
-	var nus []coldp.NameUsage
-	cfg := gnparser.NewConfig(
-		gnparser.OptWithDetails(true),
-	)
-	gnp := gnparser.New(cfg)
-	for _, v := range n.data {
-		status := coldp.AcceptedTS
-		nu := coldp.NameUsage{
-			ID:                   v.taxonID,
-			TaxonomicStatus:      status,
-			Rank:                 coldp.NewRank(v.rank),
-			ScientificNameString: v.nameStr,
-			ScientificName:       v.canonical,
-			ParentID:             v.parentID,
-		}
-		data.AddParsedData(gnp, &nu)
+// insertBatch converts a batch of nodes to name usages and other records
+// and saves them to SFGA. Names are parsed by ParserJobsNum workers
+// concurrently.
+func (n *ncbi) insertBatch(ctx context.Context, ds []datum) error {
+	// accepted names go first, so synonyms of a node can be found by the
+	// number of synonyms before it.
+	all := make([]coldp.NameUsage, 0, 2*len(ds))
+	for _, v := range ds {
+		all = append(all, acceptedUsage(v))
+	}
+	for _, v := range ds {
+		all = append(all, n.synonymUsages(v)...)
+	}
+	data.ParseBatch(n.gnp, all)
+
+	var verns []coldp.Vernacular
+	var tms []coldp.TypeMaterial
+	var props []coldp.TaxonProperty
+	var inters []coldp.SpeciesInteraction
+	var rels []coldp.NameRelation
+	nus := make([]coldp.NameUsage, 0, len(all))
+	rep := n.Report()
+	synIdx := len(ds)
+	for i, v := range ds {
+		syns := all[synIdx : synIdx+len(v.synonyms)]
+		synIdx += len(v.synonyms)
+
+		nu := all[i]
 		if !isParsedOK(&nu) {
-			rejectedNum++
+			rep.Reject(data.Rejected{
+				Location: "taxid " + v.taxonID,
+				Reason:   "accepted name is not parsed",
+				Raw:      v.nameStr,
+			})
 			continue
 		}
 		nus = append(nus, nu)
-		var syn []coldp.NameUsage
-		if len(v.synonyms) > 0 {
-			rejectedSyn, syn = n.synonymNameUsage(gnp, v)
-			nus = append(nus, syn...)
-		}
-	}
-	fmt.Printf(`
-Imported names:    %d
-Rejected names:    %d
-Rejected synonyms: %d
-`,
-		len(nus), rejectedNum, rejectedSyn,
-	)
+		verns = append(verns, vernaculars(v)...)
+		tms = append(tms, typeMaterials(v)...)
+		props = append(props, n.taxonProperties(v)...)
+		for _, host := range v.hosts {
+			inters = append(inters, coldp.SpeciesInteraction{
+				TaxonID:                    v.taxonID,
+				RelatedTaxonScientificName: host,
+				Type:                       coldp.HasHost,
+				Remarks:                    "potential host",
+			})
+		}
+
+		seen := make(map[string]struct{}, len(syns))
+		for j, syn := range syns {
+			if !n.checkSynonym(v, syn, seen) {
+				continue
+			}
+			nus = append(nus, syn)
+			if tp, ok := nameRelations[v.synonyms[j].class]; ok {
+				rels = append(rels, coldp.NameRelation{
+					NameID:        v.taxonID,
+					RelatedNameID: syn.ID,
+					Type:          tp,
+				})
+			}
+		}
+	}
+
 	err := n.sfga.InsertNameUsages(nus)
 	if err != nil {
 		return err
 	}
+	if len(verns) > 0 {
+		if err = n.sfga.InsertVernaculars(verns); err != nil {
+			return err
+		}
+	}
+	if len(tms) > 0 {
+		if err = n.sfga.InsertTypeMaterials(tms); err != nil {
+			return err
+		}
+	}
+	if len(props) > 0 {
+		if err = n.sfga.InsertTaxonProperties(props); err != nil {
+			return err
+		}
+	}
+	if len(inters) > 0 {
+		if err = n.sfga.InsertSpeciesInteractions(inters); err != nil {
+			return err
+		}
+	}
+	if len(rels) > 0 {
+		if err = n.sfga.InsertNameRelations(rels); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
-func (n *ncbi) synonymNameUsage(
-	gnp gnparser.GNparser,
-	d datum,
-) (int, []coldp.NameUsage) {
-	var rejectNum int
-	var res []coldp.NameUsage
-	for _, v := range d.synonyms {
-		nu := coldp.NameUsage{
-			ID:                   gnuuid.New(v.name).String(),
+func acceptedUsage(d datum) coldp.NameUsage {
+	return coldp.NameUsage{
+		ID:                   d.taxonID,
+		AlternativeID:        alternativeID(d.oldIDs),
+		TaxonomicStatus:      coldp.AcceptedTS,
+		Rank:                 coldp.NewRank(d.rank),
+		ScientificNameString: d.nameStr,
+		ScientificName:       d.canonical,
+		ParentID:             d.parentID,
+		Species:              d.lineage[0],
+		Genus:                d.lineage[1],
+		Family:               d.lineage[2],
+		Order:                d.lineage[3],
+		Class:                d.lineage[4],
+		Phylum:               d.lineage[5],
+		Kingdom:              d.lineage[6],
+	}
+}
+
+// synonymUsages returns synonyms of a node. Their IDs are derived from the
+// taxid and the name string, because the same name can be a synonym of
+// several nodes.
+func (n *ncbi) synonymUsages(d datum) []coldp.NameUsage {
+	res := make([]coldp.NameUsage, len(d.synonyms))
+	for i, v := range d.synonyms {
+		nameString := v.nameString
+		if nameString == "" {
+			nameString = v.name
+		}
+		res[i] = coldp.NameUsage{
+			ID:                   data.DerivedID(n.cfg.Label, d.taxonID, nameString),
 			ParentID:             d.taxonID,
-			ScientificNameString: v.name,
+			ScientificNameString: nameString,
 			ScientificName:       v.name,
-			TaxonomicStatus:      coldp.SynonymTS,
+			TaxonomicStatus:      synonymClasses[v.class],
 		}
-		data.AddParsedData(gnp, &nu)
-		if !isParsedOK(&nu) {
-			rejectNum++
+		if v.class != "synonym" {
+			res[i].NameRemarks = "NCBI name class: " + v.class
+		}
+	}
+	return res
+}
+
+// checkSynonym returns true if a parsed synonym can be saved. IDs of
+// synonyms depend on the taxid, so duplicates can only occur within the
+// node, seen keeps IDs of its saved synonyms.
+func (n *ncbi) checkSynonym(
+	d datum,
+	nu coldp.NameUsage,
+	seen map[string]struct{},
+) bool {
+	reason := "synonym is not parsed"
+	if isParsedOK(&nu) {
+		if _, ok := seen[nu.ID]; !ok {
+			seen[nu.ID] = struct{}{}
+			return true
+		}
+		reason = "duplicate synonym"
+	}
+	n.Report().Reject(data.Rejected{
+		Location: "taxid " + d.taxonID,
+		Reason:   reason,
+		Raw:      nu.ScientificNameString,
+	})
+	return false
+}
+
+// vernaculars returns common names, blast names and acronyms of a node.
+// GenBank common names are preferred, classes of other names are kept in
+// remarks.
+func vernaculars(d datum) []coldp.Vernacular {
+	res := make([]coldp.Vernacular, 0, len(d.vernNames))
+	for _, v := range d.vernNames {
+		vern := coldp.Vernacular{
+			TaxonID:  d.taxonID,
+			Name:     v.value,
+			Language: vernClasses[v.class],
+			Preferred: sql.NullBool{
+				Bool:  v.class == "genbank common name",
+				Valid: true,
+			},
+		}
+		if !strings.HasSuffix(v.class, "common name") {
+			vern.Remarks = "NCBI name class: " + v.class
+		}
+		res = append(res, vern)
+	}
+	return res
+}
+
+// typeMaterials returns type strains and specimens of a node. Their status
+// is kept in remarks, if it is not one of type statuses of CoLDP, for
+// example "type strain".
+func typeMaterials(d datum) []coldp.TypeMaterial {
+	res := make([]coldp.TypeMaterial, 0, len(d.typeMaterial))
+	for _, v := range d.typeMaterial {
+		tm := coldp.TypeMaterial{
+			NameID:   d.taxonID,
+			Citation: v.identifier,
+			Status:   coldp.NewTypeStatus(v.status),
+		}
+		if tm.Status == coldp.UnknownTS {
+			tm.Remarks = v.status
+		}
+		res = append(res, tm)
+	}
+	return res
+}
+
+// taxonProperties returns the division and genetic codes of a node. Values
+// of genetic codes are their IDs, the same as translation tables of
+// GenBank, names of the codes are in remarks.
+func (n *ncbi) taxonProperties(d datum) []coldp.TaxonProperty {
+	var res []coldp.TaxonProperty
+	if div, ok := n.divisions[d.divisionID]; ok {
+		res = append(res, coldp.TaxonProperty{
+			TaxonID:  d.taxonID,
+			Property: "division",
+			Value:    div.name,
+			Remarks:  div.code,
+		})
+	}
+	codes := []struct{ property, id string }{
+		{"genetic code", d.gencodeID},
+		{"mitochondrial genetic code", d.mitoCodeID},
+	}
+	for _, v := range codes {
+		// 0 is for unspecified genetic code.
+		if v.id == "" || v.id == "0" {
 			continue
 		}
-		res = append(res, nu)
+		res = append(res, coldp.TaxonProperty{
+			TaxonID:  d.taxonID,
+			Property: v.property,
+			Value:    v.id,
+			Remarks:  n.gencodes[v.id],
+		})
+	}
+	return res
+}
+
+// alternativeID returns taxids merged into a node as alternative IDs.
+func alternativeID(oldIDs []string) string {
+	res := make([]string, len(oldIDs))
+	for i, v := range oldIDs {
+		res[i] = "ncbi:" + v
 	}
-	return rejectNum, res
+	return strings.Join(res, ",")
 }
 
 func isParsedOK(nu *coldp.NameUsage) bool {
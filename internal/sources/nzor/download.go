@@ -6,42 +6,33 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log/slog"
 	"net/http"
 	"os"
-	"strconv"
 	"time"
 
-	"github.com/gnames/gn"
 	"github.com/sfborg/harvester/internal/sysio"
+	"github.com/sfborg/harvester/pkg/event"
 )
 
 const apiURL = "https://data.nzor.org.nz/v1/names"
 
 // Download fetches all NZOR names pages into a local JSONL file.
-// It is resumable: if nzor.jsonl already exists from a previous partial run,
+// It is resumable: if nzor.jsonl exists from a previous interrupted run,
 // download continues from the next page rather than starting over.
-// If nzor.jsonl is absent (fresh run or wiped by another dataset), the cache
-// is reset first so no stale files from other datasets linger.
-func (n *nzor) Download() (string, error) {
+// A new harvest starts from scratch, the cache is reset if there is no
+// nzor.jsonl, if the previous download was complete (nzor.jsonl.done
+// exists), or if WithForce is set.
+// On cancellation it stops between pages; pages written so far are kept.
+func (n *nzor) Download(ctx context.Context) (string, error) {
 	if n.cfg.SkipDownload {
 		return "", nil
 	}
 
-	// nzor.jsonl absent → fresh start; wipe any leftover cache from other datasets.
-	// nzor.jsonl present → resuming; preserve it.
-	if _, err := os.Stat(n.jsonlPath); os.IsNotExist(err) {
-		if err := sysio.ResetCache(n.cfg); err != nil {
-			return "", err
-		}
-	}
-
-	// Done sentinel present → download already complete.
-	if _, err := os.Stat(n.donePath); err == nil {
-		slog.Info("NZOR data already downloaded, skipping")
-		gn.Info("NZOR data already downloaded, skipping")
-		return "", nil
+	if n.isResumed() {
+		slog.Info("resuming NZOR download")
+	} else if err := sysio.ResetCache(n.cfg); err != nil {
+		return "", err
 	}
 
 	startPage := countValidLines(n.jsonlPath) + 1
@@ -52,20 +43,22 @@ func (n *nzor) Download() (string, error) {
 	}
 	defer f.Close()
 
-	ctx := context.Background()
 	w := bufio.NewWriter(f)
 
 	slog.Info("downloading NZOR", "startPage", startPage)
-	gn.Info(fmt.Sprintf("Downloading NZOR starting from page %d", startPage))
+	n.cfg.Info("Downloading NZOR starting from page %d", startPage)
 
 	for page := startPage; ; page++ {
 		if page%100 == 0 {
 			slog.Info("downloading NZOR", "page", page)
-			gn.Info(fmt.Sprintf("Downloading NZOR page %d", page))
+			n.cfg.Info("Downloading NZOR page %d", page)
 		}
 
 		body, err := n.fetchPage(ctx, page)
 		if err != nil {
+			if ctx.Err() != nil {
+				return "", n.interrupted(ctx, page-startPage)
+			}
 			return "", err
 		}
 
@@ -86,7 +79,9 @@ func (n *nzor) Download() (string, error) {
 			break
 		}
 
-		time.Sleep(100 * time.Millisecond)
+		if err := sleep(ctx, 100*time.Millisecond); err != nil {
+			return "", n.interrupted(ctx, page-startPage+1)
+		}
 	}
 
 	if err := os.WriteFile(n.donePath, []byte("done"), 0644); err != nil {
@@ -94,77 +89,67 @@ func (n *nzor) Download() (string, error) {
 	}
 
 	slog.Info("NZOR download complete")
-	gn.Info("NZOR download complete")
+	n.cfg.Info("NZOR download complete")
 	return "", nil
 }
 
-func (n *nzor) Extract(_ string) error {
-	return nil
+// isResumed returns true if an interrupted download of nzor.jsonl can be
+// continued.
+func (n *nzor) isResumed() bool {
+	if n.cfg.WithForce {
+		return false
+	}
+	if _, err := os.Stat(n.donePath); err == nil {
+		return false
+	}
+	_, err := os.Stat(n.jsonlPath)
+	return err == nil
 }
 
-// fetchPage retrieves a single API page with exponential-backoff retries.
-// Transient errors (network failures, 5xx, 429) are retried up to 5 times.
-// Client errors (4xx except 429) are returned immediately.
-func (n *nzor) fetchPage(ctx context.Context, pageNum int) ([]byte, error) {
-	url := fmt.Sprintf("%s?page=%d", apiURL, pageNum)
-	const maxRetries = 5
-	var lastErr error
-
-	for attempt := range maxRetries {
-		if attempt > 0 {
-			wait := time.Duration(1<<uint(attempt)) * time.Second
-			slog.Warn("NZOR fetch error, retrying",
-				"page", pageNum, "attempt", attempt, "wait", wait, "err", lastErr)
-			time.Sleep(wait)
-		}
-
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-		if err != nil {
-			return nil, err
-		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := n.http.Do(req)
-		if err != nil {
-			lastErr = err
-			continue
-		}
-
-		if resp.StatusCode == http.StatusTooManyRequests {
-			resp.Body.Close()
-			wait := time.Duration(1<<uint(attempt)) * time.Second
-			if ra := resp.Header.Get("Retry-After"); ra != "" {
-				if secs, err := strconv.Atoi(ra); err == nil {
-					wait = time.Duration(secs) * time.Second
-				}
-			}
-			lastErr = fmt.Errorf("rate limited (HTTP 429)")
-			time.Sleep(wait)
-			continue
-		}
-
-		if resp.StatusCode >= 500 {
-			resp.Body.Close()
-			lastErr = fmt.Errorf("server error: HTTP %d", resp.StatusCode)
-			continue
-		}
+// interrupted reports how many pages were kept after a canceled download.
+func (n *nzor) interrupted(ctx context.Context, pages int) error {
+	slog.Warn("NZOR download interrupted", "pagesKept", pages)
+	n.cfg.Notify(event.Event{
+		Type: event.Warning,
+		Message: fmt.Sprintf(
+			"NZOR download interrupted, %d new pages kept in %s",
+			pages, n.jsonlPath,
+		),
+	})
+	return ctx.Err()
+}
 
-		if resp.StatusCode >= 400 {
-			resp.Body.Close()
-			return nil, fmt.Errorf("client error: HTTP %d", resp.StatusCode)
-		}
+func (n *nzor) Extract(ctx context.Context, _ string) error {
+	return ctx.Err()
+}
 
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			lastErr = fmt.Errorf("reading response body: %w", err)
-			continue
-		}
+// sleep pauses for d or until ctx is canceled.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
 
-		return body, nil
+// fetchPage retrieves a single API page. Transient errors (network
+// failures, 5xx, 429) are retried by the HTTP client.
+func (n *nzor) fetchPage(ctx context.Context, pageNum int) ([]byte, error) {
+	url := n.cfg.ResolveURL(fmt.Sprintf("%s?page=%d", apiURL, pageNum))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
 	}
+	req.Header.Set("Accept", "application/json")
 
-	return nil, fmt.Errorf("page %d failed after %d attempts: %w", pageNum, maxRetries, lastErr)
+	body, err := n.http.Fetch(req)
+	if err != nil {
+		return nil, fmt.Errorf("page %d: %w", pageNum, err)
+	}
+	return body, nil
 }
 
 // countValidLines returns the number of leading lines in path that are valid JSON.
@@ -0,0 +1,87 @@
+package nzor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// nzorServer serves two pages of names and an empty third page, and
+// records requested pages.
+func nzorServer() (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var pages []string
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			page := r.URL.Query().Get("page")
+			mu.Lock()
+			pages = append(pages, page)
+			mu.Unlock()
+			if page == "3" {
+				fmt.Fprint(w, `{"names":[]}`)
+				return
+			}
+			fmt.Fprintf(w, `{"names":[{"nameId":"%s"}]}`, page)
+		},
+	))
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		res := pages
+		pages = nil
+		return res
+	}
+}
+
+func TestDownload(t *testing.T) {
+	assert := assert.New(t)
+	srv, pages := nzorServer()
+	defer srv.Close()
+
+	dir := t.TempDir()
+	newNZOR := func(force bool) *nzor {
+		cfg := config.New(
+			config.OptCacheDir(dir),
+			config.OptMirrors(map[string]string{"": srv.URL}),
+			config.OptHTTPRetries(0),
+			config.OptWithForce(force),
+		)
+		return New(cfg).(*nzor)
+	}
+	ctx := context.Background()
+
+	n := newNZOR(false)
+	_, err := n.Download(ctx)
+	assert.Nil(err)
+	assert.Equal([]string{"1", "2", "3"}, pages())
+	assert.FileExists(n.donePath)
+	assert.Equal(3, countValidLines(n.jsonlPath))
+
+	// a complete download is not reused by the next harvest.
+	_, err = n.Download(ctx)
+	assert.Nil(err)
+	assert.Equal([]string{"1", "2", "3"}, pages())
+	assert.Equal(3, countValidLines(n.jsonlPath))
+
+	// an interrupted download is resumed.
+	assert.Nil(os.Remove(n.donePath))
+	assert.Nil(os.WriteFile(n.jsonlPath, []byte(`{"names":[]}`+"\n"), 0644))
+	_, err = n.Download(ctx)
+	assert.Nil(err)
+	assert.Equal([]string{"2", "3"}, pages())
+	assert.Equal(3, countValidLines(n.jsonlPath))
+
+	// with force an interrupted download starts over.
+	assert.Nil(os.Remove(n.donePath))
+	n = newNZOR(true)
+	_, err = n.Download(ctx)
+	assert.Nil(err)
+	assert.Equal([]string{"1", "2", "3"}, pages())
+}
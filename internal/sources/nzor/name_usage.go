@@ -2,16 +2,17 @@ package nzor
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 
-	"github.com/dustin/go-humanize"
 	"github.com/gnames/gnfmt/gnlang"
 	"github.com/gnames/gnlib/ent/nomcode"
 	"github.com/gnames/gnparser"
 	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/harvester/pkg/event"
 	"github.com/sfborg/sflib/pkg/coldp"
 )
 
@@ -58,10 +59,8 @@ type nzorHierEntry struct {
 
 const nzorLinkBase = "https://www.nzor.org.nz/names/"
 
-func (n *nzor) importNameUsages() error {
-	gnp := gnparser.New(gnparser.NewConfig(
-		gnparser.OptWithDetails(true),
-	))
+func (n *nzor) importNameUsages(ctx context.Context) error {
+	gnp := data.NewParser(n.cfg)
 
 	f, err := os.Open(n.jsonlPath)
 	if err != nil {
@@ -96,7 +95,6 @@ func (n *nzor) importNameUsages() error {
 			switch nm.Class {
 			case "Scientific Name":
 				nu := buildNameUsage(nm)
-				data.AddParsedData(gnp, nu)
 				nuBatch = append(nuBatch, *nu)
 				totalNU++
 			case "Vernacular Name":
@@ -108,7 +106,9 @@ func (n *nzor) importNameUsages() error {
 		}
 
 		if len(nuBatch) >= n.cfg.BatchSize {
-			if err := n.flushBatch(nuBatch, vernBatch, totalNU, totalVern); err != nil {
+			if err := n.flushBatch(
+				ctx, gnp, nuBatch, vernBatch, totalNU, totalVern,
+			); err != nil {
 				return err
 			}
 			nuBatch = nuBatch[:0]
@@ -121,26 +121,40 @@ func (n *nzor) importNameUsages() error {
 	}
 
 	if len(nuBatch) > 0 || len(vernBatch) > 0 {
-		if err := n.flushBatch(nuBatch, vernBatch, totalNU, totalVern); err != nil {
+		if err := n.flushBatch(
+			ctx, gnp, nuBatch, vernBatch, totalNU, totalVern,
+		); err != nil {
 			return err
 		}
 	}
 
-	fmt.Fprintf(os.Stderr, "\n")
+	n.cfg.Notify(event.Event{
+		Type:  event.Processed,
+		Count: int64(totalVern),
+		Unit:  "vernaculars",
+		Done:  true,
+	})
 	return nil
 }
 
 func (n *nzor) flushBatch(
+	ctx context.Context,
+	gnp gnparser.GNparser,
 	nuBatch []coldp.NameUsage,
 	vernBatch []coldp.Vernacular,
 	totalNU, totalVern int,
 ) error {
-	fmt.Fprint(os.Stderr, "\r", strings.Repeat(" ", 80))
-	fmt.Fprintf(os.Stderr, "\rProcessed %s names, %s vernaculars",
-		humanize.Comma(int64(totalNU)),
-		humanize.Comma(int64(totalVern)),
-	)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	n.cfg.Notify(event.Event{
+		Type: event.Processed, Count: int64(totalNU), Unit: "names",
+	})
+	n.cfg.Notify(event.Event{
+		Type: event.Processed, Count: int64(totalVern), Unit: "vernaculars",
+	})
 	if len(nuBatch) > 0 {
+		data.ParseBatch(gnp, nuBatch)
 		if err := n.sfga.InsertNameUsages(nuBatch); err != nil {
 			return err
 		}
@@ -1,11 +1,10 @@
 package nzor
 
 import (
-	"net/http"
 	"path/filepath"
-	"time"
 
-	"github.com/sfborg/harvester/internal/base"
+	"github.com/sfborg/harvester/internal/httpclient"
+	"github.com/sfborg/harvester/pkg/base"
 	"github.com/sfborg/harvester/pkg/config"
 	"github.com/sfborg/harvester/pkg/data"
 	"github.com/sfborg/sflib/pkg/sfga"
@@ -15,7 +14,7 @@ type nzor struct {
 	data.Convertor
 	cfg       config.Config
 	sfga      sfga.Archive
-	http      *http.Client
+	http      *httpclient.Client
 	jsonlPath string
 	donePath  string
 }
@@ -34,21 +33,13 @@ successfully downloaded page.`,
 		ManualSteps: false,
 		URL:         "https://data.nzor.org.nz/v1/names",
 	}
+	cfg = cfg.ForLabel(set.Label)
 	res := nzor{
 		cfg:       cfg,
 		Convertor: base.New(cfg, &set),
-		http:      httpClient(),
+		http:      httpclient.Shared(cfg),
 		jsonlPath: filepath.Join(cfg.ExtractDir, "nzor.jsonl"),
 		donePath:  filepath.Join(cfg.ExtractDir, "nzor.jsonl.done"),
 	}
 	return &res
 }
-
-func httpClient() *http.Client {
-	tr := &http.Transport{
-		MaxIdleConns:      10,
-		IdleConnTimeout:   600 * time.Second,
-		ForceAttemptHTTP2: false,
-	}
-	return &http.Client{Timeout: 5 * time.Minute, Transport: tr}
-}
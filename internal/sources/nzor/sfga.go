@@ -1,24 +1,24 @@
 package nzor
 
 import (
+	"context"
 	"log/slog"
 
-	"github.com/gnames/gn"
 	"github.com/sfborg/sflib/pkg/sfga"
 )
 
-func (n *nzor) ToSfga(sfga sfga.Archive) error {
+func (n *nzor) ToSfga(ctx context.Context, sfga sfga.Archive) error {
 	n.sfga = sfga
 
 	slog.Info("importing Meta")
-	gn.Info("Importing Meta")
+	n.cfg.Info("Importing Meta")
 	if err := n.importMeta(); err != nil {
 		return err
 	}
 
 	slog.Info("importing Name Usages")
-	gn.Info("Importing Name Usages")
-	if err := n.importNameUsages(); err != nil {
+	n.cfg.Info("Importing Name Usages")
+	if err := n.importNameUsages(ctx); err != nil {
 		return err
 	}
 
@@ -12,7 +12,7 @@ import (
 	"github.com/sfborg/sflib/pkg/coldp"
 )
 
-func (p *paleodb) importNameUsages() (
+func (p *paleodb) importNameUsages(ctx context.Context) (
 	map[string]string, map[string][]string, error,
 ) {
 	cit := make(map[string]string)
@@ -28,9 +28,21 @@ func (p *paleodb) importNameUsages() (
 	var wg sync.WaitGroup
 	wg.Add(1)
 
+	// readCtx stops reading when the sample is complete.
+	readCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	smp := data.NewSampler(p.cfg)
+
 	go func() {
 		defer wg.Done()
 		for rows := range ch {
+			rows = smp.Rows(rows)
+			if smp.Done() {
+				cancel()
+			}
+			if len(rows) == 0 {
+				continue
+			}
 
 			nus := make([]coldp.NameUsage, 0, len(rows[0]))
 			verns := make([]coldp.Vernacular, 0, len(rows[0]))
@@ -108,20 +120,23 @@ func (p *paleodb) importNameUsages() (
 					nu.Extinct = coldp.ToBool(true)
 				}
 
-				data.AddParsedData(p.p, &nu)
 				nus = append(nus, nu)
 			}
+			data.ParseBatch(p.p, nus)
 			p.sfga.InsertNameUsages(nus)
 			p.sfga.InsertVernaculars(verns)
 		}
 	}()
 
-	_, err = csv.ReadChunks(context.Background(), ch, p.cfg.BatchSize)
+	_, err = csv.ReadChunks(readCtx, ch, p.cfg.BatchSize)
+	close(ch)
+	wg.Wait()
 	if err != nil {
 		return nil, nil, err
 	}
-	close(ch)
-
-	wg.Wait()
+	// ReadChunks does not report cancellation.
+	if err = ctx.Err(); err != nil {
+		return nil, nil, err
+	}
 	return cit, types, nil
 }
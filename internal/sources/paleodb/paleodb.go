@@ -2,12 +2,11 @@ package paleodb
 
 import (
 	"database/sql"
-	"net/http"
-	"time"
 
 	"github.com/gnames/gnlib/ent/nomcode"
 	"github.com/gnames/gnparser"
-	"github.com/sfborg/harvester/internal/base"
+	"github.com/sfborg/harvester/internal/httpclient"
+	"github.com/sfborg/harvester/pkg/base"
 	"github.com/sfborg/harvester/pkg/config"
 	"github.com/sfborg/harvester/pkg/data"
 	"github.com/sfborg/sflib/pkg/sfga"
@@ -19,7 +18,7 @@ type paleodb struct {
 	set  data.DataSet
 	sfga sfga.Archive
 	db   *sql.DB
-	http *http.Client
+	http *httpclient.Client
 	p    gnparser.GNparser
 }
 
@@ -31,26 +30,13 @@ func New(cfg config.Config) data.Convertor {
 		ManualSteps: false,
 		URL:         "https://paleobiodb.org/data1.2",
 	}
+	cfg = cfg.ForLabel(set.Label)
 	res := paleodb{
 		cfg:       cfg,
 		Convertor: base.New(cfg, &set),
 		set:       set,
-		http:      httpClient(),
-		p: gnparser.New(
-			gnparser.NewConfig(
-				gnparser.OptWithDetails(true),
-				gnparser.OptCode(nomcode.Botanical),
-			),
-		),
+		http:      httpclient.Shared(cfg),
+		p:         data.NewParser(cfg, gnparser.OptCode(nomcode.Botanical)),
 	}
 	return &res
 }
-
-func httpClient() *http.Client {
-	tr := &http.Transport{
-		MaxIdleConns:      10,
-		IdleConnTimeout:   600 * time.Second,
-		ForceAttemptHTTP2: false,
-	}
-	return &http.Client{Timeout: 5 * time.Minute, Transport: tr}
-}
@@ -1,43 +1,46 @@
 package paleodb
 
 import (
+	"context"
 	"log/slog"
 
-	"github.com/gnames/gn"
 	"github.com/sfborg/sflib/pkg/sfga"
 )
 
-// ToSfga imports the ION archive into a sfga archive.
-func (p *paleodb) ToSfga(sfga sfga.Archive) error {
+// ToSfga imports the PaleoDB data into a sfga archive.
+func (p *paleodb) ToSfga(ctx context.Context, sfga sfga.Archive) error {
 	var err error
 	var citations map[string]string
 	var types map[string][]string
 	p.sfga = sfga
 
 	slog.Info("importing Meta")
-	gn.Info("Importing Meta")
+	p.cfg.Info("Importing Meta")
 	err = p.importMeta()
 	if err != nil {
 		return err
 	}
 
 	slog.Info("importing Names Usages")
-	gn.Info("Importing Names Usages")
-	citations, types, err = p.importNameUsages()
+	p.cfg.Info("Importing Names Usages")
+	citations, types, err = p.importNameUsages(ctx)
 	if err != nil {
 		return err
 	}
 
 	slog.Info("importing Refernces")
-	gn.Info("Importing Refernces")
+	p.cfg.Info("Importing Refernces")
+	if err = ctx.Err(); err != nil {
+		return err
+	}
 	err = p.importReferences(citations)
 	if err != nil {
 		return err
 	}
 
 	slog.Info("importing Type Materials")
-	gn.Info("Importing Type Materials")
-	err = p.importTypeMaterials(types)
+	p.cfg.Info("Importing Type Materials")
+	err = p.importTypeMaterials(ctx, types)
 	if err != nil {
 		return err
 	}
@@ -2,19 +2,19 @@ package paleodb
 
 import (
 	"context"
-	"fmt"
-	"os"
 	"path/filepath"
-	"strings"
 	"sync"
 
-	"github.com/dustin/go-humanize"
 	"github.com/gnames/gnfmt/gncsv"
 	"github.com/gnames/gnfmt/gncsv/config"
+	"github.com/sfborg/harvester/pkg/event"
 	"github.com/sfborg/sflib/pkg/coldp"
 )
 
-func (p *paleodb) importTypeMaterials(types map[string][]string) error {
+func (p *paleodb) importTypeMaterials(
+	ctx context.Context,
+	types map[string][]string,
+) error {
 	var err error
 	ch := make(chan []string)
 	var wg sync.WaitGroup
@@ -29,12 +29,12 @@ func (p *paleodb) importTypeMaterials(types map[string][]string) error {
 
 	go p.processType(csv, types, ch, &wg)
 
-	_, err = csv.Read(context.Background(), ch)
+	_, err = csv.Read(ctx, ch)
+	close(ch)
+	wg.Wait()
 	if err != nil {
 		return err
 	}
-	close(ch)
-	wg.Wait()
 	return nil
 }
 
@@ -50,8 +50,9 @@ func (p *paleodb) processType(
 	for v := range ch {
 		count++
 		if count%1_000 == 0 {
-			fmt.Fprint(os.Stderr, "\r", strings.Repeat(" ", 80))
-			fmt.Fprintf(os.Stderr, "\rProcessed %s lines", humanize.Comma(int64(count)))
+			p.cfg.Notify(event.Event{
+				Type: event.Processed, Count: int64(count), Unit: "lines",
+			})
 		}
 		specID := csv.F(v, "specimen_no")
 		taxonIDs, ok := types[specID]
@@ -73,5 +74,7 @@ func (p *paleodb) processType(
 		}
 	}
 	p.sfga.InsertTypeMaterials(res)
-	fmt.Fprintf(os.Stderr, "\r%s\r", strings.Repeat(" ", 80))
+	p.cfg.Notify(event.Event{
+		Type: event.Processed, Count: int64(count), Unit: "lines", Done: true,
+	})
 }
@@ -1,6 +1,7 @@
 package wcvp
 
 import (
+	"context"
 	"encoding/csv"
 	"fmt"
 	"io"
@@ -8,10 +9,10 @@ import (
 	"regexp"
 	"strings"
 
-	"github.com/dustin/go-humanize"
 	"github.com/gnames/gnlib/ent/nomcode"
 	"github.com/gnames/gnparser"
 	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/harvester/pkg/event"
 	"github.com/sfborg/sflib/pkg/coldp"
 )
 
@@ -19,11 +20,8 @@ const powoLinkBase = "https://powo.science.kew.org/taxon/urn:lsid:ipni.org:names
 
 var yearRe = regexp.MustCompile(`\d{4}`)
 
-func (w *wcvp) importNameUsages() error {
-	gnp := gnparser.New(gnparser.NewConfig(
-		gnparser.OptCode(nomcode.Botanical),
-		gnparser.OptWithDetails(true),
-	))
+func (w *wcvp) importNameUsages(ctx context.Context) error {
+	gnp := data.NewParser(w.cfg, gnparser.OptCode(nomcode.Botanical))
 
 	f, err := os.Open(w.csvPath)
 	if err != nil {
@@ -43,8 +41,9 @@ func (w *wcvp) importNameUsages() error {
 
 	var total int
 	var batch []coldp.NameUsage
+	smp := data.NewSampler(w.cfg)
 
-	for {
+	for !smp.Done() {
 		row, err := r.Read()
 		if err == io.EOF {
 			break
@@ -54,15 +53,13 @@ func (w *wcvp) importNameUsages() error {
 		}
 
 		nu := buildNameUsage(row, idx, w.refMap)
-		if nu == nil {
+		if nu == nil || !smp.Keep() {
 			continue
 		}
-		data.AddParsedData(gnp, nu)
-
 		total++
 		batch = append(batch, *nu)
 		if len(batch) >= w.cfg.BatchSize {
-			if err := w.flushBatch(batch, total); err != nil {
+			if err := w.flushBatch(ctx, gnp, batch, total); err != nil {
 				return err
 			}
 			batch = batch[:0]
@@ -70,18 +67,30 @@ func (w *wcvp) importNameUsages() error {
 	}
 
 	if len(batch) > 0 {
-		if err := w.flushBatch(batch, total); err != nil {
+		if err := w.flushBatch(ctx, gnp, batch, total); err != nil {
 			return err
 		}
 	}
 
-	fmt.Fprintf(os.Stderr, "\n")
+	w.cfg.Notify(event.Event{
+		Type: event.Processed, Count: int64(total), Unit: "names", Done: true,
+	})
 	return nil
 }
 
-func (w *wcvp) flushBatch(batch []coldp.NameUsage, total int) error {
-	fmt.Fprint(os.Stderr, "\r", strings.Repeat(" ", 80))
-	fmt.Fprintf(os.Stderr, "\rProcessed %s names", humanize.Comma(int64(total)))
+func (w *wcvp) flushBatch(
+	ctx context.Context,
+	gnp gnparser.GNparser,
+	batch []coldp.NameUsage,
+	total int,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data.ParseBatch(gnp, batch)
+	w.cfg.Notify(event.Event{
+		Type: event.Processed, Count: int64(total), Unit: "names",
+	})
 	return w.sfga.InsertNameUsages(batch)
 }
 
@@ -1,6 +1,7 @@
 package wcvp
 
 import (
+	"context"
 	"encoding/csv"
 	"fmt"
 	"io"
@@ -10,7 +11,7 @@ import (
 	"github.com/sfborg/sflib/pkg/coldp"
 )
 
-func (w *wcvp) importReferences() error {
+func (w *wcvp) importReferences(ctx context.Context) error {
 	f, err := os.Open(w.csvPath)
 	if err != nil {
 		return fmt.Errorf("opening WCVP csv for references: %w", err)
@@ -68,6 +69,9 @@ func (w *wcvp) importReferences() error {
 		refs = append(refs, ref)
 
 		if len(refs) >= w.cfg.BatchSize {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			if err := w.sfga.InsertReferences(refs); err != nil {
 				return err
 			}
@@ -1,30 +1,30 @@
 package wcvp
 
 import (
+	"context"
 	"log/slog"
 
-	"github.com/gnames/gn"
 	"github.com/sfborg/sflib/pkg/sfga"
 )
 
-func (w *wcvp) ToSfga(sfga sfga.Archive) error {
+func (w *wcvp) ToSfga(ctx context.Context, sfga sfga.Archive) error {
 	w.sfga = sfga
 
 	slog.Info("importing Meta")
-	gn.Info("Importing Meta")
+	w.cfg.Info("Importing Meta")
 	if err := w.importMeta(); err != nil {
 		return err
 	}
 
 	slog.Info("importing References")
-	gn.Info("Importing References")
-	if err := w.importReferences(); err != nil {
+	w.cfg.Info("Importing References")
+	if err := w.importReferences(ctx); err != nil {
 		return err
 	}
 
 	slog.Info("importing Name Usages")
-	gn.Info("Importing Name Usages")
-	if err := w.importNameUsages(); err != nil {
+	w.cfg.Info("Importing Name Usages")
+	if err := w.importNameUsages(ctx); err != nil {
 		return err
 	}
 
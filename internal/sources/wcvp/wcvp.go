@@ -1,10 +1,10 @@
 package wcvp
 
 import (
+	"context"
 	"path/filepath"
 
-	"github.com/gnames/gn"
-	"github.com/sfborg/harvester/internal/base"
+	"github.com/sfborg/harvester/pkg/base"
 	"github.com/sfborg/harvester/pkg/config"
 	"github.com/sfborg/harvester/pkg/data"
 	"github.com/sfborg/sflib/pkg/sfga"
@@ -28,6 +28,7 @@ https://sftp.kew.org/pub/data-repositories/WCVP/wcvp.zip`,
 		ManualSteps: false,
 		URL:         "https://sftp.kew.org/pub/data-repositories/WCVP/wcvp.zip",
 	}
+	cfg = cfg.ForLabel(set.Label)
 	res := wcvp{
 		cfg:       cfg,
 		Convertor: base.New(cfg, &set),
@@ -35,9 +36,9 @@ https://sftp.kew.org/pub/data-repositories/WCVP/wcvp.zip`,
 	return &res
 }
 
-func (w *wcvp) Extract(path string) error {
-	gn.Info("Extracting WCVP data")
-	if err := w.Convertor.Extract(path); err != nil {
+func (w *wcvp) Extract(ctx context.Context, path string) error {
+	w.cfg.Info("Extracting WCVP data")
+	if err := w.Convertor.Extract(ctx, path); err != nil {
 		return err
 	}
 	w.csvPath = filepath.Join(w.cfg.ExtractDir, "wcvp_names.csv")
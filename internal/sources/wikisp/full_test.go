@@ -1,6 +1,7 @@
 package wikisp
 
 import (
+	"context"
 	"database/sql"
 	"os"
 	"path/filepath"
@@ -15,7 +16,7 @@ import (
 func TestFullParsing(t *testing.T) {
 	// Create temp directory for output
 	tmpDir := t.TempDir()
-	extractDir := filepath.Join(tmpDir, "extract")
+	extractDir := filepath.Join(tmpDir, "wikispecies", "extract")
 
 	// Copy test file to extract dir
 	err := os.MkdirAll(extractDir, 0755)
@@ -40,23 +41,23 @@ func TestFullParsing(t *testing.T) {
 
 	// Extract (pass the XML file path, not the directory)
 	xmlPath := filepath.Join(extractDir, "wikisp_pages.xml")
-	if err := convertor.Extract(xmlPath); err != nil {
+	if err := convertor.Extract(context.Background(), xmlPath); err != nil {
 		t.Fatal(err)
 	}
 
 	// Initialize SFGA
-	sfgaArchive, err := convertor.InitSfga()
+	sfgaArchive, err := convertor.InitSfga(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	// Convert to SFGA
-	if err := convertor.ToSfga(sfgaArchive); err != nil {
+	if err := convertor.ToSfga(context.Background(), sfgaArchive); err != nil {
 		t.Fatalf("ToSfga failed: %v", err)
 	}
 
 	// Open database for validation
-	dbPath := filepath.Join(tmpDir, "sfga", "schema.sqlite")
+	dbPath := filepath.Join(tmpDir, "wikispecies", "sfga", "schema.sqlite")
 	db, err := sql.Open("sqlite", dbPath)
 	if err != nil {
 		t.Fatal(err)
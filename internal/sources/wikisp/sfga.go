@@ -15,9 +15,8 @@ import (
 
 	"github.com/gnames/gn"
 	"github.com/gnames/gnlib/ent/nomcode"
-	"github.com/gnames/gnparser"
-	"github.com/gnames/gnuuid"
 	"github.com/sfborg/harvester/internal/sources/wikisp/wsparser"
+	"github.com/sfborg/harvester/pkg/config"
 	"github.com/sfborg/harvester/pkg/data"
 	"github.com/sfborg/harvester/pkg/errcode"
 	"github.com/sfborg/sflib/pkg/coldp"
@@ -25,7 +24,7 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
-func (w *wikisp) ToSfga(sfga sfga.Archive) error {
+func (w *wikisp) ToSfga(ctx context.Context, sfga sfga.Archive) error {
 	w.sfga = sfga
 
 	// Insert metadata
@@ -42,16 +41,16 @@ func (w *wikisp) ToSfga(sfga sfga.Archive) error {
 	}
 
 	chIn := make(chan string)
-	g, ctx := errgroup.WithContext(context.Background())
+	g, gCtx := errgroup.WithContext(ctx)
 
 	g.Go(func() error {
 		defer close(chIn)
-		err := w.readXML(ctx, chIn)
+		err := w.readXML(gCtx, chIn)
 		return err
 	})
 
 	g.Go(func() error {
-		err := w.parsePages(ctx, chIn)
+		err := w.parsePages(gCtx, chIn)
 		return err
 	})
 
@@ -59,10 +58,11 @@ func (w *wikisp) ToSfga(sfga sfga.Archive) error {
 		return err
 	}
 
-	return nil
+	// cancellation from the caller is not swallowed
+	return ctx.Err()
 }
 
-func (w *wikisp) parsePages(_ context.Context, chIn <-chan string) error {
+func (w *wikisp) parsePages(ctx context.Context, chIn <-chan string) error {
 	slog.Info("starting WikiSpecies parsing")
 
 	// PASS 1: Parse and categorize all pages
@@ -70,10 +70,13 @@ func (w *wikisp) parsePages(_ context.Context, chIn <-chan string) error {
 		w.parsePage(pageStr)
 		w.stats.TotalPages++
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	slog.Info("pass 1 complete", "total_pages", w.stats.TotalPages,
 		"taxon_pages", w.stats.TaxonPages)
-	gn.Info(
+	w.cfg.Info(
 		"Pass 1 complete: %d pages, %d taxa",
 		w.stats.TotalPages, w.stats.TaxonPages,
 	)
@@ -83,6 +86,7 @@ func (w *wikisp) parsePages(_ context.Context, chIn <-chan string) error {
 		acceptedID := w.storage.taxonIDs[to]
 		if acceptedID == "" {
 			w.stats.RedirectTargetNotFound++
+			w.reject("redirect "+from, "redirect target is not found", to)
 			w.stats.MissingRedirectTargets[to] = append(
 				w.stats.MissingRedirectTargets[to], from)
 			continue
@@ -91,22 +95,30 @@ func (w *wikisp) parsePages(_ context.Context, chIn <-chan string) error {
 		w.addSynonymFromRedirect(from, acceptedID)
 	}
 
-	slog.Info("pass 2 complete", "synonyms", len(w.synonymMap))
-	gn.Info("Pass 2 complete: %d synonyms", len(w.synonymMap))
+	var synonymsNum int
+	for _, v := range w.synonymMap {
+		synonymsNum += len(v)
+	}
+	slog.Info("pass 2 complete", "synonyms", synonymsNum)
+	w.cfg.Info("Pass 2 complete: %d synonyms", synonymsNum)
 
 	// PASS 3: Create NameUsage entries
 	nameUsages, vernaculars := w.createNameUsages()
 
 	// Process synonyms
-	for _, syn := range w.synonymMap {
-		nu := createSynonymNameUsage(syn, w.gnp)
-		nameUsages = append(nameUsages, nu)
-		w.stats.SynonymsTotal++
+	synonyms := make([]coldp.NameUsage, 0, synonymsNum)
+	for _, v := range w.synonymMap {
+		for _, syn := range v {
+			synonyms = append(synonyms, createSynonymNameUsage(w.cfg.Label, syn))
+			w.stats.SynonymsTotal++
+		}
 	}
+	data.ParseBatch(w.gnp, synonyms)
+	nameUsages = append(nameUsages, synonyms...)
 
 	slog.Info("pass 3 complete", "name_usages", len(nameUsages),
 		"vernaculars", len(vernaculars))
-	gn.Info(
+	w.cfg.Info(
 		"Pass 3 complete: %d name_usages, %d vernaculars",
 		len(nameUsages), len(vernaculars),
 	)
@@ -123,7 +135,7 @@ func (w *wikisp) parsePages(_ context.Context, chIn <-chan string) error {
 	}
 
 	// Log final statistics
-	logStats(w.stats)
+	logStats(w.cfg, w.stats)
 
 	return nil
 }
@@ -140,6 +152,7 @@ func (w *wikisp) createNameUsages() ([]coldp.NameUsage, []coldp.Vernacular) {
 			w.stats.NamesAccepted++
 		} else {
 			w.stats.NamesRejected++
+			w.reject("page "+pd.ID, "name is not valid", pd.ScientificName)
 		}
 
 		// Vernacular names
@@ -152,10 +165,21 @@ func (w *wikisp) createNameUsages() ([]coldp.NameUsage, []coldp.Vernacular) {
 			vernaculars = append(vernaculars, vn)
 		}
 	}
+
+	data.ParseBatch(w.gnp, nameUsages)
+	for _, nu := range nameUsages {
+		// Warn about low quality parse
+		if nu.ParseQuality.Valid && nu.ParseQuality.Int64 > 2 {
+			slog.Warn("low quality parse",
+				"name", nu.ScientificName,
+				"quality", nu.ParseQuality.Int64,
+				"link", nu.Link)
+		}
+	}
 	return nameUsages, vernaculars
 }
 
-func (w *wikisp) readXML(_ context.Context, chIn chan<- string) error {
+func (w *wikisp) readXML(ctx context.Context, chIn chan<- string) error {
 	elements, err := os.ReadDir(w.cfg.ExtractDir)
 	if err != nil {
 		return err
@@ -178,19 +202,28 @@ func (w *wikisp) readXML(_ context.Context, chIn chan<- string) error {
 
 	var inPage bool
 	var page []string
-	for scanner.Scan() {
+	smp := data.NewSampler(w.cfg)
+	for !smp.Done() && scanner.Scan() {
 		line := scanner.Text()
 		if !inPage && pageStart.MatchString(line) {
 			inPage = true
 		}
-		if inPage {
-			page = append(page, line)
-			if pageEnd.MatchString(line) {
-				chIn <- strings.Join(page, "\n")
-				page = nil
-				inPage = false
+		if !inPage {
+			continue
+		}
+		page = append(page, line)
+		if !pageEnd.MatchString(line) {
+			continue
+		}
+		if smp.Keep() {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case chIn <- strings.Join(page, "\n"):
 			}
 		}
+		page = nil
+		inPage = false
 	}
 	if err := scanner.Err(); err != nil {
 		return err
@@ -203,6 +236,8 @@ func (w *wikisp) parsePage(pageStr string) {
 	var page PageXML
 	if err := xml.Unmarshal([]byte(pageStr), &page); err != nil {
 		w.stats.SkippedInvalidXML++
+		loc := fmt.Sprintf("page %d of the dump", w.stats.TotalPages+1)
+		w.reject(loc, "invalid XML page", pageStr)
 		slog.Warn("invalid XML", "error", err)
 		return
 	}
@@ -225,6 +260,8 @@ func (w *wikisp) parsePage(pageStr string) {
 		if err != nil {
 			if !errcode.Is(err, errcode.WikispSkipPage) {
 				w.stats.TaxonPagesFailed++
+				loc := fmt.Sprintf("page %d", page.ID)
+				w.reject(loc, "taxon page is not parsed", page.Title)
 			}
 			return
 		}
@@ -616,6 +653,7 @@ func (w *wikisp) addSynonymFromSection(
 		slog.Debug("parse failed for synonym",
 			"name", synName, "error", err)
 		w.stats.SynonymsParseFailed++
+		w.reject("page "+acceptedID, "synonym is not parsed", synName)
 		return
 	}
 
@@ -623,13 +661,15 @@ func (w *wikisp) addSynonymFromSection(
 	authorship := wsParsed.Authorship
 	quality := wsParsed.Quality
 
-	if existing, ok := w.synonymMap[canonicalName]; ok {
+	synonyms := w.synonyms(canonicalName)
+	if existing, ok := synonyms[authorship]; ok {
 		// Already exists - just mark it
 		existing.HasSynonymSection = true
 		w.stats.SynonymDuplicates++
+		w.reject("page "+acceptedID, "duplicate synonym", synName)
 	} else {
-		// New synonym
-		w.synonymMap[canonicalName] = &synonym{
+		// New synonym, homonyms with other authors are kept
+		synonyms[authorship] = &synonym{
 			CanonicalName:     canonicalName,
 			Authorship:        authorship,
 			Quality:           quality,
@@ -658,7 +698,14 @@ func (w *wikisp) addSynonymFromRedirect(
 	authorship := wsParsed.Authorship
 	quality := wsParsed.Quality
 
-	if existing, ok := w.synonymMap[canonicalName]; ok {
+	synonyms := w.synonyms(canonicalName)
+	existing, ok := synonyms[authorship]
+	if !ok && authorship == "" {
+		// Redirects usually have no authorship, they are merged with
+		// a synonym of the same taxon, or with the only synonym of the name.
+		existing, ok = findSynonym(synonyms, acceptedID)
+	}
+	if ok {
 		// Already exists from synonym section - merge!
 		existing.HasRedirect = true
 
@@ -672,9 +719,10 @@ func (w *wikisp) addSynonymFromRedirect(
 		existing.AcceptedID = acceptedID
 
 		w.stats.SynonymDuplicates++
+		w.reject("redirect "+from, "duplicate synonym", from)
 	} else {
 		// New synonym from redirect only
-		w.synonymMap[canonicalName] = &synonym{
+		synonyms[authorship] = &synonym{
 			CanonicalName: canonicalName,
 			Authorship:    authorship,
 			Quality:       quality,
@@ -684,6 +732,33 @@ func (w *wikisp) addSynonymFromRedirect(
 	}
 }
 
+// synonyms returns synonyms with the given canonical form by their
+// authorships. Homonyms with different authorships are different synonyms.
+func (w *wikisp) synonyms(canonical string) map[string]*synonym {
+	res, ok := w.synonymMap[canonical]
+	if !ok {
+		res = make(map[string]*synonym)
+		w.synonymMap[canonical] = res
+	}
+	return res
+}
+
+// findSynonym returns a synonym of the accepted taxon, or the only
+// synonym.
+func findSynonym(
+	synonyms map[string]*synonym,
+	acceptedID string,
+) (*synonym, bool) {
+	var res *synonym
+	for _, v := range synonyms {
+		if v.AcceptedID == acceptedID {
+			return v, true
+		}
+		res = v
+	}
+	return res, len(synonyms) == 1
+}
+
 // createNameUsageWithValidation creates a NameUsage from PageData with
 // validation.
 func (w *wikisp) createNameUsageWithValidation(
@@ -707,16 +782,6 @@ func (w *wikisp) createNameUsageWithValidation(
 		Link:                 makeWikiURL(pd.Title),
 	}
 
-	data.AddParsedData(w.gnp, &nu)
-
-	// Warn about low quality parse
-	if nu.ParseQuality.Valid && nu.ParseQuality.Int64 > 2 {
-		slog.Warn("low quality parse",
-			"name", pd.ScientificName,
-			"quality", nu.ParseQuality.Int64,
-			"title", pd.Title)
-	}
-
 	// Resolve parent
 	if pd.ParentTemplate != "" {
 		if parentID, found := resolveParentID(pd.ParentTemplate, w.storage); found {
@@ -768,19 +833,18 @@ func resolveParentID(
 	return "", false
 }
 
-// createSynonymNameUsage creates a NameUsage for a synonym.
-func createSynonymNameUsage(syn *synonym, gnp gnparser.GNparser) coldp.NameUsage {
+// createSynonymNameUsage creates a NameUsage for a synonym. Its ID is
+// derived from the accepted ID and the name with authorship.
+func createSynonymNameUsage(label string, syn *synonym) coldp.NameUsage {
 	// Extract canonical from syn.Name (format: "syn-X => canonical")
 	canonical := syn.CanonicalName
 
 	// Build full scientific name string (canonical + authorship)
-	scientificNameString := canonical
-	if syn.Authorship != "" {
-		scientificNameString = canonical + " " + syn.Authorship
-	}
+	scientificNameString := syn.nameString()
+	id := data.DerivedID(label, syn.AcceptedID, scientificNameString)
 
 	nu := coldp.NameUsage{
-		ID:                   gnuuid.New(syn.CanonicalName).String(),
+		ID:                   id,
 		ScientificName:       canonical,            // Canonical name
 		ScientificNameString: scientificNameString, // Full name with authorship
 		CanonicalFull:        canonical,            // Just canonical
@@ -790,13 +854,20 @@ func createSynonymNameUsage(syn *synonym, gnp gnparser.GNparser) coldp.NameUsage
 		Code:                 nomcode.Unknown,
 	}
 
-	data.AddParsedData(gnp, &nu)
-
 	return nu
 }
 
+// reject adds a record dropped during parsing to the harvest report.
+func (w *wikisp) reject(location, reason, raw string) {
+	w.Report().Reject(data.Rejected{
+		Location: location,
+		Reason:   reason,
+		Raw:      raw,
+	})
+}
+
 // logStats logs final parsing statistics.
-func logStats(stats *parseStats) {
+func logStats(cfg config.Config, stats *parseStats) {
 	slog.Info("WikiSpecies parsing complete",
 		"total_pages", stats.TotalPages,
 		"taxa_processed", stats.TaxonPages,
@@ -810,12 +881,12 @@ func logStats(stats *parseStats) {
 		"redirects", stats.SkippedRedirects,
 		"redirect_target_not_found", stats.RedirectTargetNotFound,
 	)
-	gn.Info("WikiSpecies parsing complete")
+	cfg.Info("WikiSpecies parsing complete")
 
 	// Log missing parent templates
 	if len(stats.MissingParents) > 0 {
 		slog.Warn("missing parent templates", "count", len(stats.MissingParents))
-		gn.Info("Missing %d parent templates", len(stats.MissingParents))
+		cfg.Info("Missing %d parent templates", len(stats.MissingParents))
 		for template, taxa := range stats.MissingParents {
 			slog.Info("missing parent template",
 				"template", template,
@@ -828,7 +899,7 @@ func logStats(stats *parseStats) {
 		slog.Warn(
 			"missing redirect targets", "count", len(stats.MissingRedirectTargets),
 		)
-		gn.Info("Missing %d redirect targets", len(stats.MissingRedirectTargets))
+		cfg.Info("Missing %d redirect targets", len(stats.MissingRedirectTargets))
 		for target, redirects := range stats.MissingRedirectTargets {
 			slog.Info("missing redirect target",
 				"target", target,
@@ -0,0 +1,76 @@
+package wikisp
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/stretchr/testify/assert"
+	_ "modernc.org/sqlite"
+)
+
+func TestSynonymHomonyms(t *testing.T) {
+	assert := assert.New(t)
+	w := New(config.New(config.OptCacheDir(t.TempDir()))).(*wikisp)
+
+	w.addSynonymFromSection("Bubo maximus Fleming, 1822", "1")
+	w.addSynonymFromSection("Bubo maximus Sibby, 1830", "1")
+	w.addSynonymFromSection("Bubo maximus Sibby, 1830", "2")
+	assert.Len(w.synonymMap["Bubo maximus"], 2)
+	assert.Equal(1, w.stats.SynonymDuplicates)
+
+	// a redirect without authorship goes to a synonym of its taxon
+	w.addSynonymFromRedirect("Bubo maximus", "1")
+	assert.Len(w.synonymMap["Bubo maximus"], 2)
+	assert.Equal(2, w.stats.SynonymDuplicates)
+
+	ids := make(map[string]struct{})
+	for _, v := range w.synonymMap["Bubo maximus"] {
+		nu := createSynonymNameUsage(w.cfg.Label, v)
+		ids[nu.ID] = struct{}{}
+	}
+	assert.Len(ids, 2)
+}
+
+func TestNameUsageIDs(t *testing.T) {
+	assert := assert.New(t)
+
+	ids := func() []string {
+		dir := t.TempDir()
+		cfg := config.New(config.OptCacheDir(dir))
+		w := New(cfg)
+		ctx := context.Background()
+		assert.Nil(w.Extract(ctx, "../../../testdata/wikisp_pages.xml"))
+		sfga, err := w.InitSfga(ctx)
+		assert.Nil(err)
+		assert.Nil(w.ToSfga(ctx, sfga))
+
+		dbPath := filepath.Join(dir, "wikispecies", "sfga", "schema.sqlite")
+		db, err := sql.Open("sqlite", dbPath)
+		assert.Nil(err)
+		defer db.Close()
+
+		rows, err := db.Query("SELECT col__id FROM name ORDER BY col__id")
+		assert.Nil(err)
+		defer rows.Close()
+		var res []string
+		for rows.Next() {
+			var id string
+			assert.Nil(rows.Scan(&id))
+			res = append(res, id)
+		}
+		return res
+	}
+
+	ids1 := ids()
+	assert.Greater(len(ids1), 10)
+	assert.Equal(ids1, ids(), "IDs are stable")
+
+	seen := make(map[string]struct{})
+	for _, v := range ids1 {
+		seen[v] = struct{}{}
+	}
+	assert.Len(seen, len(ids1), "IDs are unique")
+}
@@ -1,17 +1,17 @@
 package wikisp
 
 import (
+	"context"
 	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/gnames/gn"
 	"github.com/gnames/gnlib/ent/nomcode"
 	"github.com/gnames/gnparser"
-	"github.com/sfborg/harvester/internal/base"
 	"github.com/sfborg/harvester/internal/sources/wikisp/wsparser"
+	"github.com/sfborg/harvester/pkg/base"
 	"github.com/sfborg/harvester/pkg/config"
 	"github.com/sfborg/harvester/pkg/data"
 	"github.com/sfborg/sflib/pkg/sfga"
@@ -25,7 +25,7 @@ type wikisp struct {
 	wsp        *wsparser.WSParser
 	stats      *parseStats
 	storage    *tempStorage
-	synonymMap map[string]*synonym
+	synonymMap map[string]map[string]*synonym
 	taxonPages []*PageData
 }
 
@@ -38,10 +38,8 @@ func New(cfg config.Config) data.Convertor {
 			"specieswiki-latest-pages-articles.xml.bz2",
 	}
 
-	gnp := gnparser.New(gnparser.NewConfig(
-		gnparser.OptWithDetails(true),
-		gnparser.OptCode(nomcode.Unknown),
-	))
+	cfg = cfg.ForLabel(set.Label)
+	gnp := data.NewParser(cfg, gnparser.OptCode(nomcode.Unknown))
 	res := wikisp{
 		cfg:       cfg,
 		Convertor: base.New(cfg, &set),
@@ -56,17 +54,21 @@ func New(cfg config.Config) data.Convertor {
 		},
 		gnp:        gnp,
 		wsp:        wsparser.New(&gnparserAdapter{gnp: gnp}),
-		synonymMap: make(map[string]*synonym),
+		synonymMap: make(map[string]map[string]*synonym),
 	}
 	return &res
 }
 
 // Extract handles both compressed archives and plain XML files.
-func (w *wikisp) Extract(path string) error {
+func (w *wikisp) Extract(ctx context.Context, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	// If it's already XML, just copy it to extract directory
 	if strings.HasSuffix(strings.ToLower(path), ".xml") {
 		slog.Info("plain XML file, copying to extract directory", "path", path)
-		gn.Info("Copy XML file to extract directory")
+		w.cfg.Info("Copy XML file to extract directory")
 
 		// Create extract directory
 		if err := os.MkdirAll(w.cfg.ExtractDir, 0755); err != nil {
@@ -92,10 +94,10 @@ func (w *wikisp) Extract(path string) error {
 		}
 
 		slog.Info("copied XML file", "destination", dstPath)
-		gn.Info("XML file copied to <em>%s</em>", dstPath)
+		w.cfg.Info("XML file copied to <em>%s</em>", dstPath)
 		return nil
 	}
 
 	// Otherwise use base implementation for compressed files
-	return w.Convertor.Extract(path)
+	return w.Convertor.Extract(ctx, path)
 }
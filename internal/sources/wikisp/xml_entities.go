@@ -67,6 +67,14 @@ type synonym struct {
 	HasSynonymSection bool
 }
 
+// nameString returns the canonical form of a synonym with its authorship.
+func (s *synonym) nameString() string {
+	if s.Authorship == "" {
+		return s.CanonicalName
+	}
+	return s.CanonicalName + " " + s.Authorship
+}
+
 type parseStats struct {
 	TotalPages             int
 	SkippedNamespace       int
@@ -2,6 +2,7 @@ package worldplants
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"log/slog"
 	"os"
@@ -11,8 +12,8 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/gnames/gn"
 	"github.com/gnames/gnsys"
+	"github.com/sfborg/harvester/pkg/event"
 )
 
 // numberedFile represents a CSV file with a numeric name.
@@ -25,7 +26,7 @@ type numberedFile struct {
 // The path parameter can be either:
 // - A zip file containing ferns.csv and numbered CSV files
 // - A directory containing these files
-func (wp *worldplants) Extract(path string) error {
+func (wp *worldplants) Extract(ctx context.Context, path string) error {
 	if path == "" {
 		return fmt.Errorf(
 			"WFWP requires --file option with path to zip file or directory",
@@ -51,7 +52,11 @@ func (wp *worldplants) Extract(path string) error {
 		return err
 	}
 
-	if err := wp.preparePlants(sourceDir, extractDir); err != nil {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := wp.preparePlants(ctx, sourceDir, extractDir); err != nil {
 		return err
 	}
 
@@ -93,7 +98,7 @@ func (wp *worldplants) extractZipFile(
 	zipPath string,
 ) (string, func(), error) {
 	slog.Info("extracting zip file", "path", zipPath)
-	gn.Info("Extracting <em>%s</em>", zipPath)
+	wp.cfg.Info("Extracting <em>%s</em>", zipPath)
 
 	// Create temp directory in download dir for extraction
 	tempDir := filepath.Join(wp.cfg.DownloadDir, "wfwp-extracted")
@@ -113,13 +118,16 @@ func (wp *worldplants) extractZipFile(
 	}
 
 	slog.Info("zip file extracted", "to", tempDir)
-	gn.Info("Zip extracted to <em>%s</em>", tempDir)
+	wp.cfg.Info("Zip extracted to <em>%s</em>", tempDir)
 
 	// Cleanup function to remove temp directory
 	cleanup := func() {
 		if err := os.RemoveAll(tempDir); err != nil {
 			slog.Warn("failed to clean up temp directory", "error", err)
-			gn.Warn("Unable to clean temp directory")
+			wp.cfg.Notify(event.Event{
+				Type:    event.Warning,
+				Message: "Unable to clean temp directory",
+			})
 		}
 	}
 
@@ -190,7 +198,7 @@ func (wp *worldplants) prepareFerns(inputDir, extractDir string) error {
 	fernsOutput := filepath.Join(extractDir, "ferns.csv")
 
 	slog.Info("copying ferns.csv", "from", fernsInput, "to", fernsOutput)
-	gn.Info("Copy ferns.csv from %s to %s", fernsInput, fernsOutput)
+	wp.cfg.Info("Copy ferns.csv from %s to %s", fernsInput, fernsOutput)
 
 	data, err := os.ReadFile(fernsInput)
 	if err != nil {
@@ -206,7 +214,10 @@ func (wp *worldplants) prepareFerns(inputDir, extractDir string) error {
 }
 
 // preparePlants concatenates numbered CSV files into plants.csv.
-func (wp *worldplants) preparePlants(inputDir, extractDir string) error {
+func (wp *worldplants) preparePlants(
+	ctx context.Context,
+	inputDir, extractDir string,
+) error {
 	outputPath := filepath.Join(extractDir, "plants.csv")
 
 	slog.Info(
@@ -214,7 +225,7 @@ func (wp *worldplants) preparePlants(inputDir, extractDir string) error {
 		"input", inputDir,
 		"output", outputPath,
 	)
-	gn.Info("Concatenating plant CSV files to %s", outputPath)
+	wp.cfg.Info("Concatenating plant CSV files to %s", outputPath)
 
 	if err := wp.removeExistingPlants(outputPath); err != nil {
 		return err
@@ -225,7 +236,7 @@ func (wp *worldplants) preparePlants(inputDir, extractDir string) error {
 		return err
 	}
 
-	if err := wp.concatenateFiles(inputDir, outputPath, files); err != nil {
+	if err := wp.concatenateFiles(ctx, inputDir, outputPath, files); err != nil {
 		return err
 	}
 
@@ -234,7 +245,7 @@ func (wp *worldplants) preparePlants(inputDir, extractDir string) error {
 		"count", len(files),
 		"output", outputPath,
 	)
-	gn.Info("Concatenated plant files into <em>%s</em>", outputPath)
+	wp.cfg.Info("Concatenated plant files into <em>%s</em>", outputPath)
 
 	return nil
 }
@@ -298,6 +309,7 @@ func (wp *worldplants) findNumberedFiles(
 
 // concatenateFiles writes all numbered files to a single output file.
 func (wp *worldplants) concatenateFiles(
+	ctx context.Context,
 	inputDir string,
 	outputPath string,
 	files []numberedFile,
@@ -313,6 +325,9 @@ func (wp *worldplants) concatenateFiles(
 	defer outFile.Close()
 
 	for i, file := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if err := wp.appendFile(inputDir, outFile, file, i, len(files)); err != nil {
 			return err
 		}
@@ -390,7 +405,7 @@ func (wp *worldplants) logFileSizes(extractDir string) {
 			"path", plantsPath,
 			"size_mb", fmt.Sprintf("%.2f", sizeMB),
 		)
-		gn.Info("Created <em>%s</em>", plantsPath)
+		wp.cfg.Info("Created <em>%s</em>", plantsPath)
 	}
 
 	if info, err := os.Stat(fernsPath); err == nil {
@@ -400,6 +415,6 @@ func (wp *worldplants) logFileSizes(extractDir string) {
 			"path", fernsPath,
 			"size_mb", fmt.Sprintf("%.2f", sizeMB),
 		)
-		gn.Info("Copied <em>%s</em>", fernsPath)
+		wp.cfg.Info("Copied <em>%s</em>", fernsPath)
 	}
 }
@@ -2,14 +2,15 @@ package worldplants
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"html"
 	"log/slog"
 	"os"
 	"strings"
 
-	"github.com/gnames/gn"
 	"github.com/google/uuid"
+	"github.com/sfborg/harvester/pkg/data"
 	"github.com/sfborg/sflib/pkg/coldp"
 )
 
@@ -104,10 +105,11 @@ func validateName(name string) error {
 // buildHierarchy reads a CSV file and builds a hierarchical node structure.
 // Reference: original lines 838-911
 func (wp *worldplants) buildHierarchy(
+	ctx context.Context,
 	csvPath string,
 ) ([]hNode, map[string]hNode, error) {
 	slog.Info("building hierarchy", "file", csvPath)
-	gn.Info("Building hierarchy <em>%s</em>", csvPath)
+	wp.cfg.Info("Building hierarchy <em>%s</em>", csvPath)
 
 	file, err := os.Open(csvPath)
 	if err != nil {
@@ -145,6 +147,11 @@ func (wp *worldplants) buildHierarchy(
 		node, err := wp.getNode(line)
 		if err != nil {
 			slog.Debug("skipping node", "line", lineNum, "error", err)
+			wp.Report().Reject(data.Rejected{
+				Location: fmt.Sprintf("line %d", lineNum),
+				Reason:   "node is not valid",
+				Raw:      strings.TrimRight(line, "\r\n"),
+			})
 			continue
 		}
 
@@ -168,12 +175,15 @@ func (wp *worldplants) buildHierarchy(
 		nodeMap[node.id] = node
 
 		if lineNum%1000 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, nil, err
+			}
 			slog.Info("processed rows", "count", lineNum)
 		}
 	}
 
 	slog.Info("hierarchy built", "nodes", len(nodes))
-	gn.Info("Finished building hierarchy: <em>%d nodes</em>", len(nodes))
+	wp.cfg.Info("Finished building hierarchy: <em>%d nodes</em>", len(nodes))
 
 	return nodes, nodeMap, nil
 }
@@ -1,14 +1,12 @@
 package worldplants
 
 import (
+	"context"
 	"fmt"
-	"io"
 	"log/slog"
-	"net/http"
 	"strconv"
 	"strings"
 
-	"github.com/gnames/gn"
 	"github.com/sfborg/sflib/pkg/coldp"
 	"gopkg.in/yaml.v3"
 )
@@ -17,36 +15,24 @@ import (
 // a coldp.Meta struct.
 // Reference: original lines 585-696
 func (wp *worldplants) fetchMetadata(
+	ctx context.Context,
 	datasetID string,
 	issuedDate string,
 	version string,
 	suffix string,
 ) (*coldp.Meta, error) {
 	slog.Info("fetching metadata", "datasetID", datasetID)
-	gn.Info("Fetching metadata")
+	wp.cfg.Info("Fetching metadata")
 
 	url := fmt.Sprintf(
 		"https://api.checklistbank.org/dataset/%s.yaml",
 		datasetID,
 	)
 
-	resp, err := http.Get(url)
+	body, err := wp.http.Get(ctx, wp.cfg.ResolveURL(url))
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch metadata: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(
-			"metadata fetch returned status %d",
-			resp.StatusCode,
-		)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read metadata response: %w", err)
-	}
 
 	metadata := make(map[string]any)
 	err = yaml.Unmarshal(body, &metadata)
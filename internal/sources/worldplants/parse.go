@@ -7,6 +7,7 @@ import (
 
 	"github.com/gnames/gnparser"
 	"github.com/gnames/gnparser/ent/parsed"
+	"github.com/google/uuid"
 	"github.com/sfborg/sflib/pkg/coldp"
 )
 
@@ -243,7 +244,7 @@ func spFromAutonym(
 	}
 
 	parent := hNode{
-		id:           "",
+		id:           uuid.NewString(),
 		parentId:     "",
 		verbatimName: autonym,
 		rank:         coldp.Species,
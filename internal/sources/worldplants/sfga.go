@@ -2,14 +2,15 @@ package worldplants
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/gnames/gn"
 	"github.com/gnames/gnlib/ent/nomcode"
+	"github.com/sfborg/harvester/pkg/data"
 	"github.com/sfborg/sflib/pkg/coldp"
 	"github.com/sfborg/sflib/pkg/sfga"
 )
@@ -17,9 +18,12 @@ import (
 // ToSfga implements the main SFGA conversion logic.
 // WFWP generates TWO SFGA files (ferns and plants).
 // Reference: original lines 750-1240, 1301-1320
-func (wp *worldplants) ToSfga(sfgaArchive sfga.Archive) error {
+func (wp *worldplants) ToSfga(
+	ctx context.Context,
+	sfgaArchive sfga.Archive,
+) error {
 	slog.Info("starting WFWP SFGA conversion")
-	gn.Info("Starting WFWP SFGA conversion")
+	wp.cfg.Info("Starting WFWP SFGA conversion")
 
 	extractDir := wp.cfg.ExtractDir
 
@@ -31,8 +35,8 @@ func (wp *worldplants) ToSfga(sfgaArchive sfga.Archive) error {
 		// Process plants dataset (1141)
 		plantsPath := filepath.Join(extractDir, "plants.csv")
 		slog.Info("processing plants dataset", "path", plantsPath)
-		gn.Info("Processing plants dataset")
-		err := wp.processDataset(plantsPath, "1141", "plants", sfgaArchive)
+		wp.cfg.Info("Processing plants dataset")
+		err := wp.processDataset(ctx, plantsPath, "1141", "plants", sfgaArchive)
 		if err != nil {
 			return fmt.Errorf("failed to process plants: %w", err)
 		}
@@ -40,15 +44,15 @@ func (wp *worldplants) ToSfga(sfgaArchive sfga.Archive) error {
 		// Process ferns dataset (1140) - default
 		fernsPath := filepath.Join(extractDir, "ferns.csv")
 		slog.Info("processing ferns dataset", "path", fernsPath)
-		gn.Info("Processing ferns dataset %s", fernsPath)
-		err := wp.processDataset(fernsPath, "1140", "ferns", sfgaArchive)
+		wp.cfg.Info("Processing ferns dataset %s", fernsPath)
+		err := wp.processDataset(ctx, fernsPath, "1140", "ferns", sfgaArchive)
 		if err != nil {
 			return fmt.Errorf("failed to process ferns: %w", err)
 		}
 	}
 
 	slog.Info("WFWP SFGA conversion complete")
-	gn.Info("WFWP SFGA conversion complete")
+	wp.cfg.Info("WFWP SFGA conversion complete")
 	return nil
 }
 
@@ -61,7 +65,7 @@ func (wp *worldplants) promptDatasetChoice() string {
 	input, err := reader.ReadString('\n')
 	if err != nil {
 		slog.Warn("error reading input, using default (ferns)", "error", err)
-		gn.Warn("Error reading input, using default (ferns)")
+		wp.cfg.Warn("Error reading input, using default (ferns)")
 		return "ferns"
 	}
 
@@ -77,38 +81,43 @@ func (wp *worldplants) promptDatasetChoice() string {
 
 // processDataset processes a single dataset (ferns or plants).
 func (wp *worldplants) processDataset(
+	ctx context.Context,
 	csvPath string,
 	datasetID string,
 	suffix string,
 	sfgaArchive sfga.Archive,
 ) error {
 	slog.Info("building hierarchy", "dataset", datasetID)
-	gn.Info("Building hierarchy")
+	wp.cfg.Info("Building hierarchy")
 
 	// Build hierarchy from CSV
-	nodes, nodeMap, err := wp.buildHierarchy(csvPath)
+	nodes, nodeMap, err := wp.buildHierarchy(ctx, csvPath)
 	if err != nil {
 		return fmt.Errorf("failed to build hierarchy: %w", err)
 	}
 
 	slog.Info("generating persistent IDs", "nodes", len(nodes))
-	gn.Info("Generating persistent IDs: %d nodes", len(nodes))
+	wp.cfg.Info("Generating persistent IDs: %d nodes", len(nodes))
 
 	// Generate persistent IDs
-	persistentIDs := wp.generatePersistentIDs(nodes, nodeMap)
+	persistentIDs, duplicates := wp.generatePersistentIDs(nodes, nodeMap)
 
 	slog.Info("processing nodes to create records")
-	gn.Info("Processing nodes to create records")
+	wp.cfg.Info("Processing nodes to create records")
 
 	// Process all nodes to create records
 	records, err := wp.processAllNodes(
 		nodes,
 		persistentIDs,
+		duplicates,
 		nodeMap,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to process nodes: %w", err)
 	}
+	if err = ctx.Err(); err != nil {
+		return err
+	}
 
 	slog.Info(
 		"inserting to SFGA",
@@ -132,10 +141,11 @@ func (wp *worldplants) processDataset(
 	}
 
 	slog.Info("fetching and inserting metadata", "dataset", datasetID)
-	gn.Info("Fetching and inserting metadata")
+	wp.cfg.Info("Fetching and inserting metadata")
 
 	// Fetch and insert metadata
 	meta, err := wp.fetchMetadata(
+		ctx,
 		datasetID,
 		wp.cfg.ArchiveDate,
 		wp.cfg.ArchiveVersion,
@@ -161,50 +171,55 @@ type datasetRecords struct {
 	vernaculars   []coldp.Vernacular
 }
 
-// generatePersistentIDs generates persistent IDs for all nodes.
+// generatePersistentIDs generates persistent IDs for all nodes. Nodes with
+// the same name and the same path in the hierarchy get the same ID, all of
+// them except the first one are returned as duplicates, and their children
+// go to the first one.
 // Reference: original lines 913-939
 func (wp *worldplants) generatePersistentIDs(
 	nodes []hNode,
 	nodeMap map[string]hNode,
-) map[string]string {
-	persistentIDs := make(map[string]string)
-	uniqueIDs := make(map[string]struct{})
+) (map[string]string, map[string]struct{}) {
+	persistentIDs := make(map[string]string, len(nodes))
+	duplicates := make(map[string]struct{})
+	uniqueIDs := make(map[string]struct{}, len(nodes))
 
 	for _, node := range nodes {
-		// Generate persistent ID for this node
-		if _, exists := persistentIDs[node.id]; !exists {
-			persistentID := getPersistentId(node, nodeMap, wp.namespace, false)
-
-			// Check for duplicates
-			if _, duplicate := uniqueIDs[persistentID]; duplicate {
-				slog.Warn(
-					"duplicate persistent ID (skipping)",
-					"name", node.verbatimName,
-					"id", persistentID,
-				)
-				continue
-			}
-
-			uniqueIDs[persistentID] = struct{}{}
-			persistentIDs[node.id] = persistentID
-		}
+		persistentID := getPersistentId(node, nodeMap, wp.namespace, false)
+		persistentIDs[node.id] = persistentID
 
 		// Generate persistent ID for parent if needed
 		if node.parentId != "" {
 			if _, exists := persistentIDs[node.parentId]; !exists {
 				parentNode := nodeMap[node.parentId]
-				persistentID := getPersistentId(
+				persistentIDs[node.parentId] = getPersistentId(
 					parentNode,
 					nodeMap,
 					wp.namespace,
 					false,
 				)
-				persistentIDs[node.parentId] = persistentID
 			}
 		}
+
+		// Check for duplicates
+		if _, duplicate := uniqueIDs[persistentID]; duplicate {
+			slog.Warn(
+				"duplicate persistent ID (skipping)",
+				"name", node.verbatimName,
+				"id", persistentID,
+			)
+			wp.Report().Reject(data.Rejected{
+				Location: "id " + persistentID,
+				Reason:   "duplicate persistent ID",
+				Raw:      node.verbatimName,
+			})
+			duplicates[node.id] = struct{}{}
+			continue
+		}
+		uniqueIDs[persistentID] = struct{}{}
 	}
 
-	return persistentIDs
+	return persistentIDs, duplicates
 }
 
 // processAllNodes processes all nodes to create SFGA records.
@@ -212,6 +227,7 @@ func (wp *worldplants) generatePersistentIDs(
 func (wp *worldplants) processAllNodes(
 	nodes []hNode,
 	persistentIDs map[string]string,
+	duplicates map[string]struct{},
 	nodeMap map[string]hNode,
 ) (*datasetRecords, error) {
 	records := &datasetRecords{
@@ -232,10 +248,10 @@ func (wp *worldplants) processAllNodes(
 			slog.Info("processing node", "count", i+1, "total", len(nodes))
 		}
 
-		persistentID := persistentIDs[node.id]
-		if persistentID == "" {
-			continue // Skipped duplicate
+		if _, ok := duplicates[node.id]; ok {
+			continue
 		}
+		persistentID := persistentIDs[node.id]
 
 		// Create accepted name usage
 		acceptedUsage, err := wp.createAcceptedNameUsage(
@@ -291,7 +307,7 @@ func (wp *worldplants) processAllNodes(
 	}
 
 	slog.Info("linking basionyms", "count", len(allBasionyms))
-	gn.Info("Linking %d basionyms", len(allBasionyms))
+	wp.cfg.Info("Linking %d basionyms", len(allBasionyms))
 
 	// Link basionyms to combinations
 	err := linkBasionyms(allNameUsages, allBasionyms, wp)
@@ -407,7 +423,7 @@ func (wp *worldplants) insertToSfga(
 
 	// Insert in correct order
 	slog.Info("inserting references", "count", len(records.references))
-	gn.Info("Inserting %d references", len(records.references))
+	wp.cfg.Info("Inserting %d references", len(records.references))
 	err = sfgaArchive.InsertReferences(records.references)
 	if err != nil {
 		return fmt.Errorf("failed to insert references: %w", err)
@@ -419,6 +435,11 @@ func (wp *worldplants) insertToSfga(
 	for _, usage := range records.nameUsages {
 		if _, exists := uniqueUsages[usage.ID]; exists {
 			slog.Warn("duplicate name usage found (skipping)", "id", usage.ID)
+			wp.Report().Reject(data.Rejected{
+				Location: "id " + usage.ID,
+				Reason:   "duplicate name usage",
+				Raw:      usage.ScientificName,
+			})
 			continue
 		}
 		uniqueUsages[usage.ID] = usage
@@ -430,8 +451,8 @@ func (wp *worldplants) insertToSfga(
 		"count", len(deduplicatedUsages),
 		"duplicates_removed", len(records.nameUsages)-len(deduplicatedUsages),
 	)
-	gn.Info("Inserting %d name usages", len(deduplicatedUsages))
-	gn.Info(
+	wp.cfg.Info("Inserting %d name usages", len(deduplicatedUsages))
+	wp.cfg.Info(
 		"Removed %d duplicates", len(records.nameUsages)-len(deduplicatedUsages),
 	)
 	err = sfgaArchive.InsertNameUsages(deduplicatedUsages)
@@ -440,14 +461,14 @@ func (wp *worldplants) insertToSfga(
 	}
 
 	slog.Info("inserting distributions", "count", len(records.distributions))
-	gn.Info("Inserting %d distributions", len(records.distributions))
+	wp.cfg.Info("Inserting %d distributions", len(records.distributions))
 	err = sfgaArchive.InsertDistributions(records.distributions)
 	if err != nil {
 		return fmt.Errorf("failed to insert distributions: %w", err)
 	}
 
 	slog.Info("inserting vernaculars", "count", len(records.vernaculars))
-	gn.Info("Inserting %d vernaculars", len(records.vernaculars))
+	wp.cfg.Info("Inserting %d vernaculars", len(records.vernaculars))
 	err = sfgaArchive.InsertVernaculars(records.vernaculars)
 	if err != nil {
 		return fmt.Errorf("failed to insert vernaculars: %w", err)
@@ -0,0 +1,68 @@
+package worldplants
+
+import (
+	"archive/zip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersistentIDs(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "ferns.csv")
+	assert.Nil(unzipFile("../../../testdata/wfwp.zip", "ferns.csv", path))
+
+	ids := func() []string {
+		wp := New(config.New(config.OptCacheDir(t.TempDir()))).(*worldplants)
+		nodes, nodeMap, err := wp.buildHierarchy(context.Background(), path)
+		assert.Nil(err)
+		persistentIDs, duplicates := wp.generatePersistentIDs(nodes, nodeMap)
+		records, err := wp.processAllNodes(
+			nodes, persistentIDs, duplicates, nodeMap,
+		)
+		assert.Nil(err)
+
+		res := make([]string, len(records.nameUsages))
+		for i, v := range records.nameUsages {
+			res[i] = v.ID
+		}
+		return res
+	}
+
+	ids1 := ids()
+	assert.Greater(len(ids1), 500)
+	assert.Equal(ids1, ids(), "IDs are stable")
+
+	seen := make(map[string]struct{})
+	for _, v := range ids1 {
+		seen[v] = struct{}{}
+	}
+	assert.Len(seen, len(ids1), "IDs are unique")
+}
+
+func unzipFile(zipPath, name, path string) error {
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	f, err := r.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	_, err = io.Copy(out, f)
+	return err
+}
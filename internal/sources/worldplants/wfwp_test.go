@@ -1,6 +1,7 @@
 package worldplants_test
 
 import (
+	"context"
 	"database/sql"
 	"os"
 	"path/filepath"
@@ -34,7 +35,7 @@ func TestWorldPlantsIntegration(t *testing.T) {
 	)
 
 	// Create extract directory
-	extractDir := filepath.Join(tmpDir, "extract")
+	extractDir := filepath.Join(tmpDir, "wfwp", "extract")
 	err = os.MkdirAll(extractDir, 0755)
 	assert.NoError(err)
 
@@ -46,7 +47,7 @@ func TestWorldPlantsIntegration(t *testing.T) {
 	assert.Equal("wfwp", convertor.Label())
 
 	// Test Extract (file preparation)
-	err = convertor.Extract(testDataDir)
+	err = convertor.Extract(context.Background(), testDataDir)
 	assert.NoError(err, "Extract should succeed")
 
 	// Verify concatenated files were created
@@ -57,16 +58,16 @@ func TestWorldPlantsIntegration(t *testing.T) {
 	assert.FileExists(plantsPath, "plants.csv should be created")
 
 	// Test InitSfga
-	sfgaArchive, err := convertor.InitSfga()
+	sfgaArchive, err := convertor.InitSfga(context.Background())
 	assert.NoError(err, "InitSfga should succeed")
 	assert.NotNil(sfgaArchive)
 
 	// Test ToSfga
-	err = convertor.ToSfga(sfgaArchive)
+	err = convertor.ToSfga(context.Background(), sfgaArchive)
 	assert.NoError(err, "ToSfga should succeed")
 
 	// Verify database was created
-	sfgaDir := filepath.Join(tmpDir, "sfga")
+	sfgaDir := filepath.Join(tmpDir, "wfwp", "sfga")
 	dbPath := filepath.Join(sfgaDir, "schema.sqlite")
 	assert.FileExists(dbPath, "SFGA database should be created")
 
@@ -152,16 +153,16 @@ func TestFileConcatenation(t *testing.T) {
 	)
 
 	// Create extract directory
-	extractDir := filepath.Join(tmpDir, "extract")
+	extractDir := filepath.Join(tmpDir, "wfwp", "extract")
 	err = os.MkdirAll(extractDir, 0755)
 	assert.NoError(err)
 
 	convertor := worldplants.New(cfg)
-	err = convertor.Extract(testDataDir)
+	err = convertor.Extract(context.Background(), testDataDir)
 	assert.NoError(err)
 
 	// Read concatenated plants.csv
-	plantsPath := filepath.Join(tmpDir, "extract", "plants.csv")
+	plantsPath := filepath.Join(tmpDir, "wfwp", "extract", "plants.csv")
 	data, err := os.ReadFile(plantsPath)
 	assert.NoError(err)
 
@@ -239,14 +240,14 @@ func TestValidateInputDirectory(t *testing.T) {
 			defer os.RemoveAll(tmpDir)
 
 			// Create extract directory
-			extractDir := filepath.Join(tmpDir, "extract")
+			extractDir := filepath.Join(tmpDir, "wfwp", "extract")
 			err = os.MkdirAll(extractDir, 0755)
 			assert.NoError(err)
 
 			cfg := config.New(config.OptCacheDir(tmpDir))
 			convertor := worldplants.New(cfg)
 
-			err = convertor.Extract(inputDir)
+			err = convertor.Extract(context.Background(), inputDir)
 			if tt.wantErr {
 				assert.Error(err)
 			} else {
@@ -276,14 +277,14 @@ func TestZipFileExtraction(t *testing.T) {
 	)
 
 	// Create extract directory
-	extractDir := filepath.Join(tmpDir, "extract")
+	extractDir := filepath.Join(tmpDir, "wfwp", "extract")
 	err = os.MkdirAll(extractDir, 0755)
 	assert.NoError(err)
 
 	convertor := worldplants.New(cfg)
 
 	// Test zip extraction
-	err = convertor.Extract(zipPath)
+	err = convertor.Extract(context.Background(), zipPath)
 	assert.NoError(err, "Zip extraction should succeed")
 
 	// Verify files were extracted and processed
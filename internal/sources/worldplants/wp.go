@@ -4,7 +4,8 @@ import (
 	"github.com/gnames/gnlib/ent/nomcode"
 	"github.com/gnames/gnparser"
 	"github.com/google/uuid"
-	"github.com/sfborg/harvester/internal/base"
+	"github.com/sfborg/harvester/internal/httpclient"
+	"github.com/sfborg/harvester/pkg/base"
 	"github.com/sfborg/harvester/pkg/config"
 	"github.com/sfborg/harvester/pkg/data"
 	"github.com/sfborg/sflib/pkg/sfga"
@@ -17,6 +18,7 @@ type worldplants struct {
 	sfga      sfga.Archive
 	parser    gnparser.GNparser
 	namespace uuid.UUID
+	http      *httpclient.Client
 }
 
 var New = func(cfg config.Config) data.Convertor {
@@ -38,12 +40,14 @@ Examples:
 	}
 	parserCfg := gnparser.NewConfig(opts...)
 
+	cfg = cfg.ForLabel(set.Label)
 	res := worldplants{
 		cfg:       cfg,
 		set:       set,
 		Convertor: base.New(cfg, &set),
 		parser:    gnparser.New(parserCfg),
 		namespace: uuid.NewSHA1(uuid.NameSpaceOID, []byte("SFBORG::WFWP")),
+		http:      httpclient.Shared(cfg),
 	}
 	return &res
 }
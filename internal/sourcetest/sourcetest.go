@@ -0,0 +1,27 @@
+// Package sourcetest contains helpers for tests of data sources.
+package sourcetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/sflib/pkg/sfga"
+	"github.com/stretchr/testify/assert"
+)
+
+// Harvest extracts data from path and converts them to SFGA, the same way
+// as the get command does after the download. The caller closes the
+// returned archive.
+func Harvest(t *testing.T, c data.Convertor, path string) sfga.Archive {
+	t.Helper()
+	assert := assert.New(t)
+	ctx := context.Background()
+	assert.Nil(c.Extract(ctx, path))
+	arc, err := c.InitSfga(ctx)
+	if !assert.Nil(err) {
+		t.FailNow()
+	}
+	assert.Nil(c.ToSfga(ctx, arc))
+	return arc
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/gnames/gnsys"
 	"github.com/sfborg/harvester/pkg/config"
 	"github.com/sfborg/harvester/pkg/errcode"
+	"github.com/sfborg/sflib"
+	"github.com/sfborg/sflib/pkg/sfga"
 )
 
 func EnsureLogDir(homeDir string) error {
@@ -45,10 +47,12 @@ func LogFile(homeDir string) (*os.File, error) {
 	return file, nil
 }
 
+// ResetCache empties the cache of the data source the configuration is
+// scoped to. Caches of other sources are not touched.
 func ResetCache(cfg config.Config) error {
-	slog.Info("reset cache")
-	gn.Message("Resetting cache")
-	err := EmptyDir(cfg.CacheDir)
+	slog.Info("reset cache", "dir", cfg.LabelDir())
+	cfg.Info("Resetting cache %s", cfg.LabelDir())
+	err := EmptyDir(cfg.LabelDir())
 	if err != nil {
 		return err
 	}
@@ -70,6 +74,16 @@ func ResetCache(cfg config.Config) error {
 	return nil
 }
 
+// ResetExtract empties directories of extracted files and of SFGA of the
+// data source the configuration is scoped to. Downloaded files are kept.
+func ResetExtract(cfg config.Config) error {
+	slog.Info("reset extracted files", "dir", cfg.ExtractDir)
+	if err := EmptyDir(cfg.ExtractDir); err != nil {
+		return err
+	}
+	return EmptyDir(cfg.SfgaDir)
+}
+
 func EmptyDir(cacheDir string) error {
 	switch gnsys.GetDirState(cacheDir) {
 	case gnsys.DirAbsent:
@@ -82,3 +96,22 @@ func EmptyDir(cacheDir string) error {
 		return fmt.Errorf("cannot empty CacheDir '%s", cacheDir)
 	}
 }
+
+// OpenSfga fetches an SFGA file (sqlite, sql dump, or their zip) into a
+// working directory and connects to it.
+func OpenSfga(path, workDir string) (sfga.Archive, error) {
+	err := EmptyDir(workDir)
+	if err != nil {
+		return nil, err
+	}
+	arc := sflib.NewSfga()
+	err = arc.Fetch(path, workDir)
+	if err != nil {
+		return nil, err
+	}
+	_, err = arc.Connect()
+	if err != nil {
+		return nil, err
+	}
+	return arc, nil
+}
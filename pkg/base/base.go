@@ -0,0 +1,307 @@
+// Package base provides default implementation of data.Convertor methods.
+// Data sources, including ones outside of this module, embed it to reuse
+// Download, Extract and InitSfga, and implement only ToSfga.
+package base
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/gnames/gnparser"
+	"github.com/gnames/gnsys"
+	"github.com/sfborg/harvester/internal/fetch"
+	"github.com/sfborg/harvester/internal/httpclient"
+	"github.com/sfborg/harvester/internal/sysio"
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/harvester/pkg/event"
+	"github.com/sfborg/sflib"
+	sflibcfg "github.com/sfborg/sflib/config"
+	"github.com/sfborg/sflib/pkg/sfga"
+)
+
+// Convertor implements default methods of data.Convertor interface.
+type Convertor struct {
+	set    *data.DataSet
+	cfg    config.Config
+	gnp    gnparser.GNparser
+	report *data.HarvestReport
+}
+
+// New creates a base Convertor with configuration scoped to the label of
+// the data set.
+func New(cfg config.Config, s *data.DataSet) data.Convertor {
+	res := Convertor{
+		cfg:    cfg.ForLabel(s.Label),
+		set:    s,
+		report: data.NewHarvestReport(s, cfg.Observer),
+	}
+	gncfg := gnparser.NewConfig(
+		gnparser.OptCode(cfg.Code),
+		gnparser.OptWithDetails(true),
+	)
+	res.gnp = gnparser.New(gncfg)
+	return &res
+}
+
+func (c *Convertor) Config() config.Config {
+	return c.cfg
+}
+
+func (c *Convertor) Report() *data.HarvestReport {
+	return c.report
+}
+
+func (c *Convertor) Label() string {
+	return c.set.Label
+}
+
+func (c *Convertor) Name() string {
+	return c.set.Name
+}
+
+func (c *Convertor) Description() string {
+	return c.set.Notes
+}
+func (c *Convertor) ManualSteps() bool {
+	return c.set.ManualSteps
+}
+
+// Download returns a local file given by LoadFile, or downloads the file of
+// the data source to the download store. Extracted files are not touched
+// here, the harvester empties their directory when the input changed.
+func (c *Convertor) Download(ctx context.Context) (string, error) {
+	var err error
+
+	if err = ctx.Err(); err != nil {
+		return "", err
+	}
+
+	if c.cfg.LoadFile != "" && !fetch.IsURL(c.cfg.LoadFile) {
+		slog.Info(
+			"using local file", "source", c.set.Label, "file", c.cfg.LoadFile,
+		)
+		c.cfg.Info("Using local file for %s: %s", c.set.Label, c.cfg.LoadFile)
+		return c.cfg.LoadFile, nil
+	}
+
+	url := c.cfg.ResolveURL(c.set.URL)
+	if c.cfg.LoadFile != "" {
+		url = c.cfg.LoadFile
+	}
+	if url == "" {
+		err = errors.New("no local file or URL given")
+		return "", err
+	}
+
+	slog.Info("downloading", "source", c.set.Label, "url", url)
+	c.cfg.Info("Downloading %s", c.set.Label)
+	return fetchFile(ctx, c.cfg, c.Report(), url)
+}
+
+// fetchFile returns a file for the URL from the persistent download store,
+// downloading it only if it changed upstream. The checksum of the stored
+// file is added to the report, so the file is not read again to decide
+// if the input changed.
+func fetchFile(
+	ctx context.Context,
+	cfg config.Config,
+	rep *data.HarvestReport,
+	url string,
+) (string, error) {
+	if !fetch.IsURL(url) {
+		if err := sysio.EmptyDir(cfg.DownloadDir); err != nil {
+			return "", err
+		}
+		return download(ctx, url, cfg.DownloadDir)
+	}
+
+	hc := httpclient.Shared(cfg)
+	dl := fetch.NewDownloader(hc.HTTP())
+	dl.MaxRetries, dl.Backoff = hc.Retries(), hc.Backoff()
+	dl.Notify = cfg.Notify
+	store := fetch.NewStore(cfg.StoreDir(), dl)
+	entry, downloaded, err := store.Get(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	if !downloaded {
+		slog.Info("using stored download", "url", url, "path", entry.Path)
+		cfg.Info("File is not changed upstream, using stored copy")
+	}
+	rep.InputFile = entry.Path
+	rep.InputSHA256, rep.InputSize = entry.SHA256, entry.Size
+	return entry.Path, nil
+}
+
+// download copies a file given by a URL that is not HTTP, like a file of a
+// directory mirror, to dir. Copying stops when the context is canceled, and
+// the partial file is removed.
+func download(ctx context.Context, rawURL, dir string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("unsupported URL scheme: %s", u.Scheme)
+	}
+	src, err := os.Open(u.Path)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	path := filepath.Join(dir, filepath.Base(u.Path))
+	dst, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	_, err = io.Copy(dst, ctxReader{ctx: ctx, r: src})
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+// ctxReader stops reading when the context is canceled.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+func (c *Convertor) Extract(ctx context.Context, path string) error {
+	// If path is empty (skip download mode), assume files are already
+	// extracted and skip this step.
+	if path == "" {
+		slog.Info("skip extraction (using cached files)")
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var f gnsys.Extractor
+	switch gnsys.GetFileType(path) {
+	case gnsys.ZipFT:
+		f = gnsys.ExtractZip
+	case gnsys.TarFT:
+		f = gnsys.ExtractTar
+	case gnsys.TarGzFT:
+		f = gnsys.ExtractTarGz
+	case gnsys.Bz2FT:
+		f = gnsys.ExtractBz2
+	case gnsys.TarBzFT:
+		f = gnsys.ExtractTarBz2
+	case gnsys.TarXzFt:
+		f = gnsys.ExtractTarXz
+	case gnsys.XzFT:
+		f = gnsys.ExtractXz
+	default:
+		return fmt.Errorf("cannot determine file format of '%s'", path)
+	}
+	err := os.MkdirAll(c.cfg.ExtractDir, 0755)
+	if err != nil {
+		return err
+	}
+	if err = f(path, c.cfg.ExtractDir); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// CopyToExtractDir copies a data file that does not need extraction to
+// ExtractDir of the configuration and returns the path of the copy. If path
+// is empty (skip download mode), it returns the file copied to ExtractDir
+// by a previous harvest.
+func CopyToExtractDir(cfg config.Config, path string) (string, error) {
+	if path == "" {
+		return extractedFile(cfg)
+	}
+	if err := os.MkdirAll(cfg.ExtractDir, 0755); err != nil {
+		return "", err
+	}
+	res := filepath.Join(cfg.ExtractDir, filepath.Base(path))
+	if _, err := gnsys.CopyFile(path, res); err != nil {
+		return "", err
+	}
+	return res, nil
+}
+
+// extractedFile finds a data file in ExtractDir. It is the copy of the
+// local file of the configuration, or the only file in the directory.
+func extractedFile(cfg config.Config) (string, error) {
+	if cfg.LoadFile != "" && !fetch.IsURL(cfg.LoadFile) {
+		res := filepath.Join(cfg.ExtractDir, filepath.Base(cfg.LoadFile))
+		if _, err := os.Stat(res); err == nil {
+			return res, nil
+		}
+	}
+
+	entries, err := os.ReadDir(cfg.ExtractDir)
+	if err != nil {
+		return "", err
+	}
+	var files []string
+	for _, v := range entries {
+		if v.Type().IsRegular() {
+			files = append(files, v.Name())
+		}
+	}
+	if len(files) != 1 {
+		return "", fmt.Errorf(
+			"expected one file in %s, found %d", cfg.ExtractDir, len(files),
+		)
+	}
+	return filepath.Join(cfg.ExtractDir, files[0]), nil
+}
+
+func (c *Convertor) InitSfga(ctx context.Context) (sfga.Archive, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	sysio.EmptyDir(c.cfg.SfgaDir)
+
+	var sflibOpts []sflibcfg.Option
+	if c.cfg.LocalSchemaPath != "" {
+		slog.Info("using local schema", "path", c.cfg.LocalSchemaPath)
+		sflibOpts = append(sflibOpts, sflibcfg.OptLocalSchemaPath(c.cfg.LocalSchemaPath))
+	}
+
+	arc := sflib.NewSfga(sflibOpts...)
+	err := arc.Create(c.cfg.SfgaDir)
+	if err != nil {
+		return nil, err
+	}
+	_, err = arc.Connect()
+	if err != nil {
+		return nil, err
+	}
+	return arc, nil
+}
+
+func (c *Convertor) ToSfga(_ context.Context, _ sfga.Archive) error {
+	slog.Info("running a placeholder ToSfga method")
+	c.cfg.Notify(event.Event{
+		Type:    event.Warning,
+		Message: "Runs a placeholder for ToSfga method",
+	})
+	return nil
+}
@@ -0,0 +1,36 @@
+package base
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownload(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	src := filepath.Join(dir, "owls.txt")
+	assert.Nil(os.WriteFile(src, []byte("Bubo bubo\n"), 0644))
+	rawURL := (&url.URL{Scheme: "file", Path: filepath.ToSlash(src)}).String()
+
+	dst := filepath.Join(dir, "download")
+	assert.Nil(os.Mkdir(dst, 0755))
+	path, err := download(context.Background(), rawURL, dst)
+	assert.Nil(err)
+	assert.Equal(filepath.Join(dst, "owls.txt"), path)
+	bs, err := os.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal("Bubo bubo\n", string(bs))
+
+	// a canceled download leaves no partial file behind.
+	assert.Nil(os.Remove(path))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = download(ctx, rawURL, dst)
+	assert.ErrorIs(err, context.Canceled)
+	assert.NoFileExists(path)
+}
@@ -0,0 +1,125 @@
+package harvester
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/sfborg/harvester/pkg/event"
+)
+
+// Status describes the outcome of a harvest in a batch.
+type Status string
+
+const (
+	StatusOK       Status = "ok"
+	StatusFailed   Status = "failed"
+	StatusCanceled Status = "canceled"
+	StatusSkipped  Status = "skipped"
+)
+
+// Task describes a harvest of one data source in a batch.
+type Task struct {
+	// Label of the data source.
+	Label string
+
+	// OutPath is the path of the resulting SFGA file.
+	OutPath string
+}
+
+// TaskResult is the outcome of a Task.
+type TaskResult struct {
+	Task
+	Status   Status
+	Duration time.Duration
+	Err      error
+
+	// Result of the harvest, it is nil if the harvest did not create files.
+	Result *Result
+}
+
+// GetBatch harvests several data sources running at most JobsNum of them
+// at the same time. A failure of one source does not stop others. Results
+// are returned in the order of the tasks.
+func (h *harvester) GetBatch(ctx context.Context, tasks []Task) []TaskResult {
+	jobs := h.cfg.JobsNum
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	res := make([]TaskResult, len(tasks))
+	sem := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+
+	for i, task := range tasks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case <-ctx.Done():
+				res[i] = TaskResult{
+					Task: task, Status: StatusCanceled, Err: ctx.Err(),
+				}
+				return
+			case sem <- struct{}{}:
+			}
+			defer func() { <-sem }()
+			res[i] = h.runTask(ctx, task)
+		}()
+	}
+
+	wg.Wait()
+	return res
+}
+
+func (h *harvester) runTask(ctx context.Context, task Task) TaskResult {
+	res := TaskResult{Task: task}
+	if err := ctx.Err(); err != nil {
+		res.Status = StatusCanceled
+		res.Err = err
+		return res
+	}
+
+	slog.Info("harvest started", "source", task.Label)
+	h.notify(task.Label, event.Info, "Harvest of <em>%s</em> started", task.Label)
+
+	start := time.Now()
+	hRes, err := h.Get(ctx, task.Label, task.OutPath)
+	res.Duration = time.Since(start)
+	res.Result = hRes
+	res.Err = err
+
+	switch {
+	case err == nil:
+		res.Status = StatusOK
+		slog.Info("harvest finished", "source", task.Label)
+		h.notify(task.Label, event.Info, "Harvest of <em>%s</em> finished", task.Label)
+	case errors.Is(err, ErrUnchanged):
+		res.Status = StatusSkipped
+	case errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded):
+		res.Status = StatusCanceled
+	default:
+		res.Status = StatusFailed
+		slog.Error("harvest failed", "source", task.Label, "error", err)
+		h.notify(task.Label, event.Warning,
+			"Harvest of <em>%s</em> failed: %s", task.Label, err)
+	}
+	return res
+}
+
+// notify sends a message about the harvest of a data source to the
+// Observer.
+func (h *harvester) notify(
+	label string,
+	tp event.Type,
+	msg string,
+	vars ...any,
+) {
+	h.cfg.Notify(event.Event{
+		Type: tp, Source: label, Message: fmt.Sprintf(msg, vars...),
+	})
+}
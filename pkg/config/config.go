@@ -1,12 +1,17 @@
 package config
 
 import (
+	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"time"
 
 	"github.com/gnames/gnfmt"
 	"github.com/gnames/gnlib/ent/nomcode"
+	"github.com/sfborg/harvester/pkg/event"
 )
 
 var (
@@ -17,7 +22,12 @@ type Config struct {
 	// CacheDir is the directory where all temporary files are located.
 	CacheDir string
 
-	// DownloadDir contains temporary files for download.
+	// Label is the label of a data source the configuration is scoped to.
+	// It is empty for a configuration that is not scoped to a source.
+	Label string
+
+	// DownloadDir contains temporary files for download. For a scoped
+	// configuration it is located in CacheDir/<label>.
 	DownloadDir string
 
 	// ExtractDir contains temporary files where original archive is extracted to.
@@ -30,6 +40,16 @@ type Config struct {
 	// stable link to a source's data the LoadFile is used.
 	LoadFile string
 
+	// LoadFiles maps labels of data sources to local files or URLs. It is
+	// used when several sources are harvested at once. ForLabel sets
+	// LoadFile from this map.
+	LoadFiles map[string]string
+
+	// Mirrors maps labels of data sources to mirrors of their remote
+	// files. A mirror is a base URL or a directory. The mirror with an
+	// empty label is used for all data sources. See ResolveURL.
+	Mirrors map[string]string
+
 	// Code provides nomenclatural code setting to use in GNparser.
 	// This flag is only important for importing data from text, csv and
 	// other ad-hoc files.
@@ -50,6 +70,10 @@ type Config struct {
 	// development to save time and bandwidth.
 	SkipDownload bool
 
+	// WithForce makes a harvest run even if the input did not change since
+	// the last successful harvest.
+	WithForce bool
+
 	// ColSep is used when importing CSV/TSV/PSV files. By default it is empty
 	// and is determined automatically.
 	ColSep string
@@ -63,19 +87,81 @@ type Config struct {
 	// to return an error, or skip them.
 	BadRow gnfmt.BadRow
 
-	// JobsNum sets the number of concurrent jobs to set, if it is
-	// needed.
+	// ColumnsFile is a YAML file that maps fields of name usages to the
+	// columns of an ad-hoc CSV/TSV/PSV file. If it is empty, columns are
+	// found by their names.
+	ColumnsFile string
+
+	// InferGenera creates genus records for species and infraspecific
+	// names from a list of names, and attaches names to them as children.
+	InferGenera bool
+
+	// NewTaxdump makes NCBI source use new_taxdump archive. Besides names
+	// and nodes it has type material, hosts and ranked lineages of taxa.
+	NewTaxdump bool
+
+	// JobsNum sets the number of data sources harvested at the same time.
 	JobsNum int
 
+	// ParserJobsNum sets the number of workers that parse names of a data
+	// source concurrently.
+	ParserJobsNum int
+
 	// BatchSize determines the size of slices to import into SFGA.
 	BatchSize int
 
+	// Limit stops conversion after the given number of primary records
+	// (names, name usages, pages) of a data source. If it is zero, all
+	// records are converted.
+	Limit int
+
+	// Root is the ID or the scientific name of a taxon. If it is set, only
+	// this taxon, its descendants and their data are kept in the archive.
+	Root string
+
+	// SampleRate is the share of primary records to convert, from 0 to 1.
+	// Records are taken at regular intervals. If it is zero, all records
+	// are converted.
+	SampleRate float64
+
 	// WithZipOutput indicates that zipped archives have to be created.
 	WithZipOutput bool
 
+	// WithValidation indicates that referential integrity of a created
+	// archive has to be checked.
+	WithValidation bool
+
+	// HTTPTimeout is the time to wait for a connection to a server and for
+	// the headers of its response.
+	HTTPTimeout time.Duration
+
+	// HTTPRetries is the number of retries of a failed HTTP request.
+	HTTPRetries int
+
+	// HTTPBackoff is the wait before the first retry of a failed HTTP
+	// request. It doubles with every following retry.
+	HTTPBackoff time.Duration
+
+	// HTTPRateLimit is the maximum number of requests per second to the
+	// same host. If it is zero, there is no limit.
+	HTTPRateLimit float64
+
+	// HTTPProxy is the URL of a proxy server. If it is empty, proxy
+	// settings are taken from HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+	// environment variables.
+	HTTPProxy string
+
+	// UserAgent is sent with every HTTP request. The harvester sets it to
+	// its name and version, if it is empty.
+	UserAgent string
+
 	// LocalSchemaPath is the path to a local schema.sql file to use
 	// instead of fetching from GitHub. Useful for development.
 	LocalSchemaPath string
+
+	// Observer receives events about progress of harvests. If it is nil,
+	// events are ignored.
+	Observer event.Observer
 }
 
 // Option is the type for all option functions available to modify
@@ -100,18 +186,60 @@ func OptLocalFile(s string) Option {
 	}
 }
 
+func OptLoadFiles(m map[string]string) Option {
+	return func(c *Config) {
+		c.LoadFiles = m
+	}
+}
+
+func OptMirrors(m map[string]string) Option {
+	return func(c *Config) {
+		c.Mirrors = m
+	}
+}
+
+func OptJobsNum(i int) Option {
+	return func(c *Config) {
+		c.JobsNum = i
+	}
+}
+
+func OptParserJobsNum(i int) Option {
+	return func(c *Config) {
+		c.ParserJobsNum = i
+	}
+}
+
+func OptBatchSize(i int) Option {
+	return func(c *Config) {
+		c.BatchSize = i
+	}
+}
+
 func OptWithZipOutput(b bool) Option {
 	return func(c *Config) {
 		c.WithZipOutput = b
 	}
 }
 
+func OptWithValidation(b bool) Option {
+	return func(c *Config) {
+		c.WithValidation = b
+	}
+}
+
 func OptSkipDownload(b bool) Option {
 	return func(c *Config) {
 		c.SkipDownload = b
 	}
 }
 
+func OptWithForce(b bool) Option {
+	return func(c *Config) {
+		c.WithForce = b
+	}
+}
+
 func OptCode(code nomcode.Code) Option {
 	return func(c *Config) {
 		c.Code = code
@@ -148,12 +276,90 @@ func OptBadRow(br gnfmt.BadRow) Option {
 	}
 }
 
+func OptColumnsFile(s string) Option {
+	return func(c *Config) {
+		c.ColumnsFile = s
+	}
+}
+
+func OptLimit(i int) Option {
+	return func(c *Config) {
+		c.Limit = i
+	}
+}
+
+func OptRoot(s string) Option {
+	return func(c *Config) {
+		c.Root = s
+	}
+}
+
+func OptSampleRate(f float64) Option {
+	return func(c *Config) {
+		c.SampleRate = f
+	}
+}
+
+func OptInferGenera(b bool) Option {
+	return func(c *Config) {
+		c.InferGenera = b
+	}
+}
+
+func OptNewTaxdump(b bool) Option {
+	return func(c *Config) {
+		c.NewTaxdump = b
+	}
+}
+
+func OptHTTPTimeout(d time.Duration) Option {
+	return func(c *Config) {
+		c.HTTPTimeout = d
+	}
+}
+
+func OptHTTPRetries(i int) Option {
+	return func(c *Config) {
+		c.HTTPRetries = i
+	}
+}
+
+func OptHTTPBackoff(d time.Duration) Option {
+	return func(c *Config) {
+		c.HTTPBackoff = d
+	}
+}
+
+func OptHTTPRateLimit(f float64) Option {
+	return func(c *Config) {
+		c.HTTPRateLimit = f
+	}
+}
+
+func OptHTTPProxy(s string) Option {
+	return func(c *Config) {
+		c.HTTPProxy = s
+	}
+}
+
+func OptUserAgent(s string) Option {
+	return func(c *Config) {
+		c.UserAgent = s
+	}
+}
+
 func OptLocalSchemaPath(s string) Option {
 	return func(c *Config) {
 		c.LocalSchemaPath = s
 	}
 }
 
+func OptObserver(o event.Observer) Option {
+	return func(c *Config) {
+		c.Observer = o
+	}
+}
+
 func New(opts ...Option) Config {
 	tmpDir := os.TempDir()
 	cacheDir, err := os.UserCacheDir()
@@ -166,20 +372,142 @@ func New(opts ...Option) Config {
 	today := currentTime.Format("2006-01-02")
 
 	res := Config{
-		CacheDir:    cacheDir,
-		JobsNum:     jobsNum,
-		Code:        nomcode.Unknown,
-		BadRow:      gnfmt.ProcessBadRow,
-		BatchSize:   50_000,
-		ArchiveDate: today,
+		CacheDir:      cacheDir,
+		JobsNum:       jobsNum,
+		ParserJobsNum: runtime.NumCPU(),
+		Code:          nomcode.Unknown,
+		BadRow:        gnfmt.ProcessBadRow,
+		BatchSize:     50_000,
+		ArchiveDate:   today,
+		HTTPTimeout:   5 * time.Minute,
+		HTTPRetries:   5,
+		HTTPBackoff:   2 * time.Second,
 	}
 	for _, opt := range opts {
 		opt(&res)
 	}
 
-	res.DownloadDir = filepath.Join(res.CacheDir, "download")
-	res.ExtractDir = filepath.Join(res.CacheDir, "extract")
-	res.SfgaDir = filepath.Join(res.CacheDir, "sfga")
+	res.setDirs(res.CacheDir)
 
 	return res
 }
+
+// ForLabel returns a copy of the configuration with download, extract and
+// sfga directories located in CacheDir/<label>. This way caches of
+// different data sources do not overwrite each other. If LoadFiles has
+// an entry for the label, it becomes the LoadFile.
+func (c Config) ForLabel(label string) Config {
+	c.Label = label
+	if f, ok := c.LoadFiles[label]; ok {
+		c.LoadFile = f
+	}
+	c.setDirs(c.LabelDir())
+	return c
+}
+
+// LabelDir returns the cache directory of the data source the
+// configuration is scoped to, or CacheDir if there is no such source.
+func (c Config) LabelDir() string {
+	if c.Label == "" {
+		return c.CacheDir
+	}
+	return filepath.Join(c.CacheDir, c.Label)
+}
+
+// ResolveURL returns the location of a remote file in the mirror of the
+// data source, or rawURL if there is no mirror. Files are expected at
+// <mirror>/<host>/<path>, the layout created by 'wget --mirror'. For a
+// directory mirror a file URL is returned, and the query of rawURL, if
+// any, is kept in the file name.
+func (c Config) ResolveURL(rawURL string) string {
+	m, ok := c.Mirrors[c.Label]
+	if !ok {
+		m = c.Mirrors[""]
+	}
+	u, err := url.Parse(rawURL)
+	if m == "" || err != nil || u.Host == "" {
+		return rawURL
+	}
+
+	if mu, err := url.Parse(m); err == nil &&
+		(mu.Scheme == "http" || mu.Scheme == "https") {
+		res := strings.TrimSuffix(m, "/") + "/" + u.Host + u.EscapedPath()
+		if u.RawQuery != "" {
+			res += "?" + u.RawQuery
+		}
+		return res
+	}
+
+	path := filepath.Join(m, u.Host, filepath.FromSlash(u.Path))
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	res := url.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+	return res.String()
+}
+
+// Notify sends the event to the Observer, if it is set. If the source of
+// the event is empty, it is set to the label of the configuration.
+func (c Config) Notify(e event.Event) {
+	if c.Observer == nil {
+		return
+	}
+	if e.Source == "" {
+		e.Source = c.Label
+	}
+	c.Observer.Notify(e)
+}
+
+// Info sends a progress message to the Observer as an Info event. The
+// message is formatted with vars, if there are any, and can contain markup
+// tags of gn package, like <em>.
+func (c Config) Info(msg string, vars ...any) {
+	c.Notify(event.Event{Type: event.Info, Message: format(msg, vars)})
+}
+
+// Warn sends a warning to the Observer as a Warning event. The message is
+// formatted the same way as by Info.
+func (c Config) Warn(msg string, vars ...any) {
+	c.Notify(event.Event{Type: event.Warning, Message: format(msg, vars)})
+}
+
+func format(msg string, vars []any) string {
+	if len(vars) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, vars...)
+}
+
+// IsPartial returns true if only a part of records of a data source is
+// kept because of Limit, SampleRate or Root.
+func (c Config) IsPartial() bool {
+	return c.Limit > 0 || (c.SampleRate > 0 && c.SampleRate < 1) ||
+		c.Root != ""
+}
+
+// StoreDir returns the directory where downloaded files are kept between
+// harvests. It is shared by all data sources and is not reset.
+func (c Config) StoreDir() string {
+	return filepath.Join(c.CacheDir, "store")
+}
+
+// WorkDir returns the temporary directory of a command such as validate
+// or diff. Like StoreDir it is located in CacheDir next to caches of data
+// sources.
+func (c Config) WorkDir(command string) string {
+	return filepath.Join(c.CacheDir, command)
+}
+
+// ReservedLabels are names of directories in CacheDir that are not caches
+// of data sources. A data source with such a label would wipe them on
+// reset of its cache.
+var ReservedLabels = []string{"store", "validate", "diff"}
+
+func (c *Config) setDirs(dir string) {
+	c.DownloadDir = filepath.Join(dir, "download")
+	c.ExtractDir = filepath.Join(dir, "extract")
+	c.SfgaDir = filepath.Join(dir, "sfga")
+}
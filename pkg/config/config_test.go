@@ -0,0 +1,55 @@
+package config_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForLabel(t *testing.T) {
+	assert := assert.New(t)
+	cfg := config.New(config.OptCacheDir("/tmp/cache"))
+	assert.Equal("", cfg.Label)
+	assert.Equal("/tmp/cache", cfg.LabelDir())
+
+	itis := cfg.ForLabel("itis")
+	ncbi := cfg.ForLabel("ncbi")
+	assert.Equal(filepath.Join("/tmp/cache", "itis"), itis.LabelDir())
+	assert.Equal(filepath.Join("/tmp/cache", "itis", "download"), itis.DownloadDir)
+	assert.Equal(filepath.Join("/tmp/cache", "itis", "extract"), itis.ExtractDir)
+	assert.Equal(filepath.Join("/tmp/cache", "itis", "sfga"), itis.SfgaDir)
+	assert.NotEqual(itis.ExtractDir, ncbi.ExtractDir)
+
+	// scoping is idempotent
+	assert.Equal(itis, itis.ForLabel("itis"))
+}
+
+func TestResolveURL(t *testing.T) {
+	assert := assert.New(t)
+	raw := "https://paleobiodb.org/data1.2/taxa/list.txt?all_taxa=true"
+	cfg := config.New(config.OptMirrors(map[string]string{
+		"paleodb": "http://localhost:8080/mirror/",
+		"":        "/data/mirror",
+	}))
+
+	tests := []struct {
+		msg, label, url, res string
+	}{
+		{"no label", "", "https://itis.gov/downloads/itisSqlite.zip",
+			"file:///data/mirror/itis.gov/downloads/itisSqlite.zip"},
+		{"url mirror", "paleodb", raw,
+			"http://localhost:8080/mirror/paleobiodb.org/data1.2/taxa/list.txt" +
+				"?all_taxa=true"},
+		{"dir mirror", "nzor", "https://data.nzor.org.nz/v1/names?page=2",
+			"file:///data/mirror/data.nzor.org.nz/v1/names%3Fpage=2"},
+		{"not url", "nzor", "", ""},
+	}
+	for _, v := range tests {
+		assert.Equal(v.res, cfg.ForLabel(v.label).ResolveURL(v.url), v.msg)
+	}
+
+	cfg = config.New()
+	assert.Equal(raw, cfg.ForLabel("paleodb").ResolveURL(raw))
+}
@@ -0,0 +1,20 @@
+package data
+
+import (
+	"strings"
+
+	"github.com/gnames/gnuuid"
+)
+
+// DerivedID returns an ID for a record that has no ID of its own in a data
+// source, for example a synonym that is only a name of an accepted taxon.
+// It is a UUID v5 of the label of the source, the ID of the record the
+// derived record belongs to and the name string. This way the same name
+// gets different IDs in different taxa and sources, while harvests of the
+// same data give the same IDs.
+func DerivedID(label, parentID, name string) string {
+	// The unit separator does not occur in names, so different parts
+	// cannot produce the same string.
+	s := strings.Join([]string{label, parentID, name}, "\x1f")
+	return gnuuid.New(s).String()
+}
@@ -0,0 +1,30 @@
+package data_test
+
+import (
+	"testing"
+
+	"github.com/sfborg/harvester/pkg/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDerivedID(t *testing.T) {
+	assert := assert.New(t)
+	id := data.DerivedID("ncbi", "9606", "Homo sapiens")
+	assert.Len(id, 36)
+	assert.Equal(id, data.DerivedID("ncbi", "9606", "Homo sapiens"))
+
+	ids := []string{
+		id,
+		data.DerivedID("ncbi", "9605", "Homo sapiens"),
+		data.DerivedID("wfwp", "9606", "Homo sapiens"),
+		data.DerivedID("ncbi", "9606", "Homo sapiens L."),
+		data.DerivedID("ncbi", "9606 Homo", "sapiens"),
+		data.DerivedID("ncbi", "9606", ""),
+		data.DerivedID("", "ncbi", "9606"),
+	}
+	seen := make(map[string]struct{})
+	for _, v := range ids {
+		seen[v] = struct{}{}
+	}
+	assert.Len(seen, len(ids), "IDs are unique")
+}
@@ -1,6 +1,8 @@
 package data
 
 import (
+	"context"
+
 	"github.com/sfborg/harvester/pkg/config"
 	"github.com/sfborg/sflib/pkg/sfga"
 )
@@ -28,6 +30,10 @@ type Accessor interface {
 	// Config returns the configuration data.
 	Config() config.Config
 
+	// Report returns the report of the current harvest. Converters add
+	// rejected records to it, the harvester fills in the rest.
+	Report() *HarvestReport
+
 	// Description provides a detailed description of the data source, including
 	// information about its data structure, update frequency, and any known
 	// limitations.  If the conversion process involves manual steps, those steps
@@ -42,26 +48,29 @@ type Accessor interface {
 }
 
 // Processor contains actions for converting source into SFGArchive.
+// Every method receives a context. When the context is canceled, an
+// implementation should stop at the nearest batch boundary, release its
+// resources (files, temporary databases) and return the context error.
 type Processor interface {
 	// Download downloads the data from the external source and stores it in a
 	// local cache. It returns the path to the downloaded file in the cache.  If
 	// the download fails, an error is returned, and the returned path may be an
 	// empty string.  Implementations should handle caching appropriately (e.g.,
 	// checking if the file already exists before downloading).
-	Download() (string, error)
+	Download(ctx context.Context) (string, error)
 
 	// Extract extracts the relevant data from the downloaded file.  The 'path'
 	// argument is the path returned by the Download() method.  The extracted
 	// data should be placed in a separate cache directory.  An error is returned
 	// if extraction fails.
-	Extract(path string) error
+	Extract(ctx context.Context, path string) error
 
 	// InitSfga creates empty sfga.Archive and returns its instance.
 	// In case of a failure it returns an error.
-	InitSfga() (sfga.Archive, error)
+	InitSfga(ctx context.Context) (sfga.Archive, error)
 
 	// ToSfga converts the extracted data to the SFGA file format.  This method
 	// should use the data previously extracted by the Extract() method.  An
 	// error is returned if the conversion fails.
-	ToSfga(sfga.Archive) error
+	ToSfga(ctx context.Context, arc sfga.Archive) error
 }
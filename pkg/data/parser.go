@@ -2,12 +2,74 @@ package data
 
 import (
 	"github.com/gnames/gnparser"
+	"github.com/gnames/gnparser/ent/parsed"
+	"github.com/sfborg/harvester/pkg/config"
 	"github.com/sfborg/sflib/pkg/coldp"
 )
 
+// NewParser creates a parser with details for AddParsedData and
+// ParseBatch. The parser uses the nomenclatural code of the configuration
+// and ParserJobsNum workers, options override them.
+func NewParser(cfg config.Config, opts ...gnparser.Option) gnparser.GNparser {
+	opts = append([]gnparser.Option{
+		gnparser.OptWithDetails(true),
+		gnparser.OptCode(cfg.Code),
+		gnparser.OptJobsNum(max(cfg.ParserJobsNum, 1)),
+	}, opts...)
+	return gnparser.New(gnparser.NewConfig(opts...))
+}
+
+// ParseBatch parses scientific name strings of name usages concurrently
+// and adds parsed data to them. Results are the same as of AddParsedData
+// called for every name usage in turn.
+func ParseBatch(p gnparser.GNparser, nus []coldp.NameUsage) {
+	names := make([]string, len(nus))
+	for i := range nus {
+		names[i] = nus[i].ScientificNameString
+	}
+	for i, v := range p.ParseNames(names) {
+		addParsed(&nus[i], v.Flatten())
+	}
+}
+
+// AmendBatch parses scientific name strings of names concurrently and
+// adds parsed data to them the same way as coldp.Name.Amend does.
+func AmendBatch(p gnparser.GNparser, ns []coldp.Name) {
+	names := make([]string, len(ns))
+	for i := range ns {
+		names[i] = ns[i].ScientificNameString
+	}
+	bp := batchParser{
+		GNparser: p,
+		parsed:   make(map[string]parsed.Parsed, len(names)),
+	}
+	for i, v := range p.ParseNames(names) {
+		bp.parsed[names[i]] = v
+	}
+	for i := range ns {
+		ns[i].Amend(bp)
+	}
+}
+
+// batchParser gives results parsed in advance to code that parses names
+// one by one.
+type batchParser struct {
+	gnparser.GNparser
+	parsed map[string]parsed.Parsed
+}
+
+func (bp batchParser) ParseName(name string) parsed.Parsed {
+	if res, ok := bp.parsed[name]; ok {
+		return res
+	}
+	return bp.GNparser.ParseName(name)
+}
+
 func AddParsedData(p gnparser.GNparser, nu *coldp.NameUsage) {
-	prsd := p.ParseName(nu.ScientificNameString).Flatten()
+	addParsed(nu, p.ParseName(nu.ScientificNameString).Flatten())
+}
 
+func addParsed(nu *coldp.NameUsage, prsd parsed.ParsedFlat) {
 	if prsd.Parsed {
 		nu.ParseQuality = coldp.ToInt(prsd.ParseQuality)
 		if prsd.ParseQuality > 2 {
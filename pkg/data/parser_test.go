@@ -0,0 +1,85 @@
+package data_test
+
+import (
+	"testing"
+
+	"github.com/gnames/gnlib/ent/nomcode"
+	"github.com/gnames/gnparser"
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/sflib/pkg/coldp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBatch(t *testing.T) {
+	assert := assert.New(t)
+	names := []string{
+		"Bubo bubo (Linnaeus, 1758)",
+		"Homo sapiens Linnaeus, 1758",
+		"Abies alba var. pendula Carrière",
+		"Tobacco mosaic virus",
+		"",
+		"not a name 123",
+		"Bubo",
+		"Ursus arctos × Ursus maritimus",
+	}
+	var nus []coldp.NameUsage
+	for range 50 {
+		for _, v := range names {
+			nus = append(nus, coldp.NameUsage{
+				ScientificNameString: v,
+			})
+		}
+	}
+	serial := make([]coldp.NameUsage, len(nus))
+	copy(serial, nus)
+
+	p := data.NewParser(config.New(config.OptParserJobsNum(4)))
+	for i := range serial {
+		data.AddParsedData(p, &serial[i])
+	}
+	data.ParseBatch(p, nus)
+	assert.Equal(serial, nus)
+	assert.Equal("Bubo bubo", nus[0].CanonicalSimple)
+	assert.Equal("Bubo", nus[len(nus)-2].CanonicalSimple)
+}
+
+func TestAmendBatch(t *testing.T) {
+	assert := assert.New(t)
+	names := []string{
+		"Bubo bubo (Linnaeus, 1758)",
+		"Abies alba var. pendula Carrière",
+		"",
+		"not a name 123",
+		"Bubo bubo (Linnaeus, 1758)",
+	}
+	var ns []coldp.Name
+	for _, v := range names {
+		ns = append(ns, coldp.Name{ScientificNameString: v})
+	}
+	serial := make([]coldp.Name, len(ns))
+	copy(serial, ns)
+
+	p := data.NewParser(config.New(config.OptParserJobsNum(4)))
+	for i := range serial {
+		serial[i].Amend(p)
+	}
+	data.AmendBatch(p, ns)
+	assert.Equal(serial, ns)
+	assert.Equal("Abies alba pendula", ns[1].CanonicalSimple)
+}
+
+func TestNewParserCode(t *testing.T) {
+	assert := assert.New(t)
+	name := "Sarracenia flava 'Maxima'"
+
+	p := data.NewParser(config.New(config.OptCode(nomcode.Cultivars)))
+	assert.Equal("Sarracenia flava ‘Maxima’", p.ParseName(name).Canonical.Simple)
+
+	// explicit options override the code of the configuration.
+	p = data.NewParser(
+		config.New(config.OptCode(nomcode.Cultivars)),
+		gnparser.OptCode(nomcode.Botanical),
+	)
+	assert.Equal("Sarracenia flava", p.ParseName(name).Canonical.Simple)
+}
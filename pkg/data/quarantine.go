@@ -0,0 +1,79 @@
+package data
+
+import (
+	"encoding/csv"
+	"os"
+	"sync"
+)
+
+// Rejected is an input record that was not converted.
+type Rejected struct {
+	// Location of the record in the input, for example "line 12" or
+	// "id 4532".
+	Location string
+
+	// Reason is a short code of the problem, for example "duplicate ID".
+	Reason string
+
+	// Raw is the content of the record as it was in the input.
+	Raw string
+}
+
+// Quarantine writes rejected records to a TSV file, so curators can review
+// them and send fixes upstream. It is safe for concurrent use.
+type Quarantine struct {
+	mu  sync.Mutex
+	f   *os.File
+	w   *csv.Writer
+	num int
+}
+
+// NewQuarantine creates a quarantine file at the path.
+func NewQuarantine(path string) (*Quarantine, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	w := csv.NewWriter(f)
+	w.Comma = '\t'
+	if err = w.Write([]string{"location", "reason", "raw"}); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &Quarantine{f: f, w: w}, nil
+}
+
+// Add writes a rejected record to the file.
+func (q *Quarantine) Add(rec Rejected) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.f == nil {
+		return os.ErrClosed
+	}
+	q.num++
+	return q.w.Write([]string{rec.Location, rec.Reason, rec.Raw})
+}
+
+// Len returns the number of records in the file.
+func (q *Quarantine) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.num
+}
+
+// Close flushes records and closes the file. It can be called more than
+// once.
+func (q *Quarantine) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.f == nil {
+		return nil
+	}
+	q.w.Flush()
+	err := q.w.Error()
+	if cerr := q.f.Close(); err == nil {
+		err = cerr
+	}
+	q.f = nil
+	return err
+}
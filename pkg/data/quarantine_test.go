@@ -0,0 +1,42 @@
+package data_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sfborg/harvester/pkg/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuarantine(t *testing.T) {
+	assert := assert.New(t)
+	path := filepath.Join(t.TempDir(), "rejected.tsv")
+	q, err := data.NewQuarantine(path)
+	assert.Nil(err)
+
+	ds := data.DataSet{Label: "test"}
+	rep := data.NewHarvestReport(&ds, nil)
+	rep.SetQuarantine(q)
+	rep.Reject(data.Rejected{
+		Location: "row 3", Reason: "duplicate ID", Raw: "3\tBubo bubo",
+	})
+	rep.Reject(data.Rejected{
+		Location: "taxid 5", Reason: "synonym is not parsed", Raw: `"Bubo" sp.`,
+	})
+	assert.Equal(2, q.Len())
+	assert.Equal(1, rep.Rejected["duplicate ID"])
+
+	assert.Nil(q.Close())
+	assert.Nil(q.Close())
+	assert.ErrorIs(q.Add(data.Rejected{}), os.ErrClosed)
+
+	res, err := os.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal(
+		"location\treason\traw\n"+
+			"row 3\tduplicate ID\t\"3\tBubo bubo\"\n"+
+			"taxid 5\tsynonym is not parsed\t\"\"\"Bubo\"\" sp.\"\n",
+		string(res),
+	)
+}
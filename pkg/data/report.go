@@ -0,0 +1,167 @@
+package data
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sfborg/harvester/pkg/event"
+)
+
+// HarvestReport contains machine-readable results of a harvest of a data
+// source. The harvester fills in general information, converters add
+// rejected records with reasons. All methods are safe for concurrent use.
+type HarvestReport struct {
+	// Label of the data source.
+	Label string `json:"label"`
+
+	// Name of the data source.
+	Name string `json:"name"`
+
+	// SourceURL is the URL of the data source, if it exists.
+	SourceURL string `json:"sourceUrl,omitempty"`
+
+	// InputFile is the downloaded or local file used for the harvest.
+	InputFile string `json:"inputFile,omitempty"`
+
+	// InputSHA256 is the checksum of the InputFile.
+	InputSHA256 string `json:"inputSha256,omitempty"`
+
+	// InputSize is the size of the InputFile in bytes.
+	InputSize int64 `json:"inputSize,omitempty"`
+
+	// OutputPath is the path of the resulting SFGA without extensions.
+	OutputPath string `json:"outputPath"`
+
+	// Version of the data given by the data source, if it is known.
+	Version string `json:"version,omitempty"`
+
+	// Issued is the date the data were published, if it is known.
+	Issued string `json:"issued,omitempty"`
+
+	// StartedAt is the time the harvest started.
+	StartedAt time.Time `json:"startedAt"`
+
+	// Stages contain durations of harvest stages in the order they ran.
+	Stages []StageReport `json:"stages"`
+
+	// Tables contain the number of records in SFGA tables.
+	Tables map[string]int `json:"tables"`
+
+	// Rejected contains the number of rejected records by reason.
+	Rejected map[string]int `json:"rejected"`
+
+	// ParseQuality is a histogram of parsing quality of names. Names
+	// without parsing information are counted under "none".
+	ParseQuality map[string]int `json:"parseQuality"`
+
+	// Validation contains the number of integrity issues by category, if
+	// the archive was validated.
+	Validation map[string]int `json:"validation,omitempty"`
+
+	observer   event.Observer
+	quarantine *Quarantine
+	mu         sync.Mutex
+}
+
+// StageReport contains the duration of one stage of a harvest.
+type StageReport struct {
+	Name    string  `json:"name"`
+	Seconds float64 `json:"seconds"`
+}
+
+// NewHarvestReport creates an empty report for a data set. Rejected
+// records are sent to the observer, if it is not nil.
+func NewHarvestReport(ds *DataSet, o event.Observer) *HarvestReport {
+	res := HarvestReport{
+		Label:     ds.Label,
+		Name:      ds.Name,
+		SourceURL: ds.URL,
+		observer:  o,
+	}
+	res.Reset()
+	return &res
+}
+
+// Reset removes results of a previous harvest, keeping information about
+// the data source.
+func (r *HarvestReport) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.InputFile = ""
+	r.InputSHA256 = ""
+	r.InputSize = 0
+	r.OutputPath = ""
+	r.Version = ""
+	r.Issued = ""
+	r.StartedAt = time.Now()
+	r.Stages = nil
+	r.Tables = make(map[string]int)
+	r.Rejected = make(map[string]int)
+	r.ParseQuality = make(map[string]int)
+	r.Validation = nil
+	r.quarantine = nil
+}
+
+// SetQuarantine sets the file for records rejected by Reject.
+func (r *HarvestReport) SetQuarantine(q *Quarantine) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.quarantine = q
+}
+
+// Reject counts a rejected record like AddRejected and keeps it in the
+// quarantine file, if it is set.
+func (r *HarvestReport) Reject(rec Rejected) {
+	r.AddRejected(rec.Reason, 1)
+
+	r.mu.Lock()
+	q := r.quarantine
+	r.mu.Unlock()
+	if q == nil {
+		return
+	}
+	if err := q.Add(rec); err != nil {
+		slog.Warn("cannot save rejected record", "reason", rec.Reason,
+			"location", rec.Location, "error", err)
+	}
+}
+
+// AddRejected adds num rejected records for the reason.
+func (r *HarvestReport) AddRejected(reason string, num int) {
+	if num == 0 {
+		return
+	}
+	r.mu.Lock()
+	r.Rejected[reason] += num
+	r.mu.Unlock()
+
+	if r.observer != nil {
+		r.observer.Notify(event.Event{
+			Type:    event.Rejected,
+			Source:  r.Label,
+			Count:   int64(num),
+			Message: reason,
+		})
+	}
+}
+
+// AddStage records the duration of a harvest stage.
+func (r *HarvestReport) AddStage(name string, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Stages = append(r.Stages, StageReport{Name: name, Seconds: d.Seconds()})
+}
+
+// Write saves the report as indented JSON to the path.
+func (r *HarvestReport) Write(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	bs, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(bs, '\n'), 0644)
+}
@@ -0,0 +1,52 @@
+package data_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/harvester/pkg/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHarvestReport(t *testing.T) {
+	assert := assert.New(t)
+	ds := data.DataSet{Label: "test", Name: "Test", URL: "https://example.org"}
+	var events []event.Event
+	obs := event.ObserverFunc(func(e event.Event) {
+		events = append(events, e)
+	})
+	rep := data.NewHarvestReport(&ds, obs)
+	rep.AddRejected("not parsed", 2)
+	rep.AddRejected("not parsed", 3)
+	rep.AddRejected("empty", 0)
+	rep.AddStage("convert", 1500*time.Millisecond)
+	assert.Equal(map[string]int{"not parsed": 5}, rep.Rejected)
+	assert.Len(events, 2)
+	assert.Equal(event.Rejected, events[1].Type)
+	assert.Equal("test", events[1].Source)
+	assert.Equal(int64(3), events[1].Count)
+	assert.Equal("not parsed", events[1].Message)
+
+	path := filepath.Join(t.TempDir(), "out.report.json")
+	err := rep.Write(path)
+	assert.Nil(err)
+
+	bs, err := os.ReadFile(path)
+	assert.Nil(err)
+	var res map[string]any
+	err = json.Unmarshal(bs, &res)
+	assert.Nil(err)
+	assert.Equal("test", res["label"])
+	assert.Equal("https://example.org", res["sourceUrl"])
+	stages := res["stages"].([]any)
+	assert.Equal(1.5, stages[0].(map[string]any)["seconds"])
+
+	rep.Reset()
+	assert.Empty(rep.Rejected)
+	assert.Empty(rep.Stages)
+	assert.Equal("test", rep.Label)
+}
@@ -0,0 +1,62 @@
+package data
+
+import (
+	"math"
+
+	"github.com/sfborg/harvester/pkg/config"
+)
+
+// Sampler selects primary records of a data source according to Limit and
+// SampleRate settings. Readers call Keep for every primary record and stop
+// reading when Done returns true. The selection is deterministic, so
+// repeated harvests of the same input produce the same sample.
+type Sampler struct {
+	limit int
+	rate  float64
+	seen  int
+	taken int
+}
+
+// NewSampler creates a Sampler out of configuration. Without Limit and
+// SampleRate it keeps all records.
+func NewSampler(cfg config.Config) *Sampler {
+	res := Sampler{limit: cfg.Limit, rate: cfg.SampleRate}
+	if res.rate <= 0 || res.rate >= 1 {
+		res.rate = 1
+	}
+	return &res
+}
+
+// Keep returns true if the next primary record belongs to the sample.
+func (s *Sampler) Keep() bool {
+	if s.Done() {
+		return false
+	}
+	s.seen++
+	if s.rate < 1 &&
+		math.Ceil(float64(s.seen)*s.rate) == math.Ceil(float64(s.seen-1)*s.rate) {
+		return false
+	}
+	s.taken++
+	return true
+}
+
+// Done returns true when the limit of records is reached.
+func (s *Sampler) Done() bool {
+	return s.limit > 0 && s.taken >= s.limit
+}
+
+// Rows returns rows of a chunk that belong to the sample. It is used with
+// readers that send rows of CSV files in chunks.
+func (s *Sampler) Rows(rows [][]string) [][]string {
+	if s.limit == 0 && s.rate == 1 {
+		return rows
+	}
+	res := rows[:0:0]
+	for _, v := range rows {
+		if s.Keep() {
+			res = append(res, v)
+		}
+	}
+	return res
+}
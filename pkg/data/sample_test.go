@@ -0,0 +1,51 @@
+package data_test
+
+import (
+	"testing"
+
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/sfborg/harvester/pkg/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampler(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg   string
+		limit int
+		rate  float64
+		kept  []int
+	}{
+		{"all", 0, 0, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{"limit", 3, 0, []int{0, 1, 2}},
+		{"rate", 0, 0.25, []int{0, 4, 8}},
+		{"rate and limit", 2, 0.5, []int{0, 2}},
+		{"rate one", 0, 1, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+	}
+
+	for _, v := range tests {
+		cfg := config.New(config.OptLimit(v.limit), config.OptSampleRate(v.rate))
+		smp := data.NewSampler(cfg)
+		var kept []int
+		for i := range 10 {
+			if smp.Done() {
+				break
+			}
+			if smp.Keep() {
+				kept = append(kept, i)
+			}
+		}
+		assert.Equal(v.kept, kept, v.msg)
+	}
+}
+
+func TestSamplerRows(t *testing.T) {
+	assert := assert.New(t)
+	rows := [][]string{{"a"}, {"b"}, {"c"}, {"d"}, {"e"}}
+	smp := data.NewSampler(config.New(config.OptLimit(4)))
+	assert.Equal(rows[:2], smp.Rows(rows[:2]))
+	assert.False(smp.Done())
+	assert.Equal(rows[2:4], smp.Rows(rows[2:]))
+	assert.True(smp.Done())
+	assert.Empty(smp.Rows(rows))
+}
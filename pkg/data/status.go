@@ -0,0 +1,50 @@
+package data
+
+import (
+	"strings"
+
+	"github.com/sfborg/sflib/pkg/coldp"
+)
+
+// statuses maps common spellings of taxonomic status that are not
+// recognized by coldp.NewTaxonomicStatus.
+var statuses = map[string]coldp.TaxonomicStatus{
+	"valid":               coldp.AcceptedTS,
+	"correct":             coldp.AcceptedTS,
+	"doubtful":            coldp.ProvisionallyAcceptedTS,
+	"provisional":         coldp.ProvisionallyAcceptedTS,
+	"invalid":             coldp.SynonymTS,
+	"homotypic synonym":   coldp.SynonymTS,
+	"homotypicsynonym":    coldp.SynonymTS,
+	"heterotypic synonym": coldp.SynonymTS,
+	"heterotypicsynonym":  coldp.SynonymTS,
+	"junior synonym":      coldp.SynonymTS,
+	"proparte synonym":    coldp.AmbiguousSynonymTS,
+	"propartesynonym":     coldp.AmbiguousSynonymTS,
+	"misapplied name":     coldp.MisappliedTS,
+}
+
+// TaxonomicStatus returns the taxonomic status for a value from an ad-hoc
+// file, Darwin Core archive and such. Unknown or empty values are treated
+// as accepted.
+func TaxonomicStatus(s string) coldp.TaxonomicStatus {
+	res := coldp.NewTaxonomicStatus(s)
+	if res != coldp.UnknownTaxSt {
+		return res
+	}
+	if res, ok := statuses[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return res
+	}
+	return coldp.AcceptedTS
+}
+
+// IsSynonym returns true if the status is one of synonym statuses.
+func IsSynonym(ts coldp.TaxonomicStatus) bool {
+	switch ts {
+	case coldp.AcceptedTS, coldp.ProvisionallyAcceptedTS, coldp.BareNameTS,
+		coldp.UnknownTaxSt:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,292 @@
+// Package diff compares two versions of the same data source in SFGA
+// format.
+package diff
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Kind is a kind of difference between two archives.
+type Kind string
+
+const (
+	// Added is a name usage that exists only in the new archive.
+	Added Kind = "added"
+
+	// Removed is a name usage that exists only in the old archive.
+	Removed Kind = "removed"
+
+	// StatusChanged is a name usage that changed between accepted and
+	// synonym.
+	StatusChanged Kind = "status"
+
+	// Reparented is a taxon with a new parent, or a synonym with a new
+	// accepted taxon.
+	Reparented Kind = "parent"
+
+	// RankChanged is a name usage with a new rank.
+	RankChanged Kind = "rank"
+
+	// AuthorshipChanged is a name usage with edited authorship.
+	AuthorshipChanged Kind = "authorship"
+
+	// VernacularAdded is a new vernacular name of a taxon.
+	VernacularAdded Kind = "vernacular added"
+
+	// VernacularRemoved is a vernacular name that a taxon lost.
+	VernacularRemoved Kind = "vernacular removed"
+
+	// DistributionAdded is a new distribution area of a taxon.
+	DistributionAdded Kind = "distribution added"
+
+	// DistributionRemoved is a distribution area that a taxon lost.
+	DistributionRemoved Kind = "distribution removed"
+)
+
+// Kinds are all kinds of differences in the order of the summary.
+var Kinds = []Kind{
+	Added, Removed, StatusChanged, Reparented, RankChanged, AuthorshipChanged,
+	VernacularAdded, VernacularRemoved, DistributionAdded, DistributionRemoved,
+}
+
+// Change is one difference between two archives.
+type Change struct {
+	Kind Kind `json:"kind"`
+
+	// ID of the name usage in the new archive, or in the old one if the
+	// usage was removed.
+	ID string `json:"id"`
+
+	// Name is the scientific name of the usage without authorship.
+	Name string `json:"name"`
+
+	// Old is the value in the old archive.
+	Old string `json:"old,omitempty"`
+
+	// New is the value in the new archive.
+	New string `json:"new,omitempty"`
+}
+
+// Result contains all differences between two archives.
+type Result struct {
+	// Counts contain the number of changes per kind.
+	Counts map[Kind]int `json:"counts"`
+
+	// Changes are sorted by kind, name and ID.
+	Changes []Change `json:"changes"`
+}
+
+// Total returns the number of changes.
+func (r *Result) Total() int {
+	return len(r.Changes)
+}
+
+// Compare finds differences between old and new archive databases. Name
+// usages are matched by ID, usages without a match are matched by their
+// scientific name.
+func Compare(ctx context.Context, oldDB, newDB *sql.DB) (*Result, error) {
+	o, err := load(ctx, oldDB)
+	if err != nil {
+		return nil, err
+	}
+	n, err := load(ctx, newDB)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := match(o, n)
+	// newIDs are IDs of matched usages in the new archive by their old IDs.
+	newIDs := make(map[string]string, len(pairs))
+	for _, v := range pairs {
+		if v.old != nil && v.new != nil {
+			newIDs[v.old.id] = v.new.id
+		}
+	}
+
+	res := Result{Counts: make(map[Kind]int)}
+	for _, v := range pairs {
+		switch {
+		case v.old == nil:
+			res.add(Change{Kind: Added, ID: v.new.id, Name: v.new.name})
+		case v.new == nil:
+			res.add(Change{Kind: Removed, ID: v.old.id, Name: v.old.name})
+		default:
+			res.compare(o, n, newIDs, v.old, v.new)
+		}
+	}
+
+	slices.SortFunc(res.Changes, func(a, b Change) int {
+		return cmp.Or(
+			cmp.Compare(slices.Index(Kinds, a.Kind), slices.Index(Kinds, b.Kind)),
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+	return &res, nil
+}
+
+func (r *Result) add(c Change) {
+	r.Counts[c.Kind]++
+	r.Changes = append(r.Changes, c)
+}
+
+func (r *Result) compare(
+	o, n *archive,
+	newIDs map[string]string,
+	ou, nu *usage,
+) {
+	// A parent can be matched by name and get a new ID, so the old parent
+	// is compared by its ID in the new archive.
+	oldParent := ou.parentID
+	if id, ok := newIDs[oldParent]; ok {
+		oldParent = id
+	}
+
+	fields := []struct {
+		kind     Kind
+		old, new string
+		changed  bool
+	}{
+		{StatusChanged, ou.status, nu.status, ou.status != nu.status},
+		{Reparented, ou.parentID, nu.parentID, oldParent != nu.parentID},
+		{RankChanged, ou.rank, nu.rank, ou.rank != nu.rank},
+		{
+			AuthorshipChanged, ou.authorship, nu.authorship,
+			ou.authorship != nu.authorship,
+		},
+	}
+	for _, v := range fields {
+		if v.changed {
+			r.add(Change{
+				Kind: v.kind, ID: nu.id, Name: nu.name, Old: v.old, New: v.new,
+			})
+		}
+	}
+
+	if ou.status != "accepted" || nu.status != "accepted" {
+		return
+	}
+	r.compareFacts(
+		o.vernaculars[ou.id], n.vernaculars[nu.id], nu,
+		VernacularAdded, VernacularRemoved,
+	)
+	r.compareFacts(
+		o.distributions[ou.id], n.distributions[nu.id], nu,
+		DistributionAdded, DistributionRemoved,
+	)
+}
+
+func (r *Result) compareFacts(
+	o, n map[string]struct{},
+	u *usage,
+	added, removed Kind,
+) {
+	for k := range n {
+		if _, ok := o[k]; !ok {
+			r.add(Change{Kind: added, ID: u.id, Name: u.name, New: k})
+		}
+	}
+	for k := range o {
+		if _, ok := n[k]; !ok {
+			r.add(Change{Kind: removed, ID: u.id, Name: u.name, Old: k})
+		}
+	}
+}
+
+// pair is a name usage in old and new archives. One of them is nil if
+// the usage was added or removed.
+type pair struct {
+	old, new *usage
+}
+
+func match(o, n *archive) []pair {
+	var res []pair
+	oldDone := make([]bool, len(o.usages))
+	newDone := make([]bool, len(n.usages))
+
+	for i := range n.usages {
+		nu := &n.usages[i]
+		if nu.id == "" {
+			continue
+		}
+		j, ok := o.byID[nu.id]
+		if !ok || oldDone[j] {
+			continue
+		}
+		oldDone[j], newDone[i] = true, true
+		res = append(res, pair{old: &o.usages[j], new: nu})
+	}
+
+	byName := make(map[string][]int)
+	for i := range o.usages {
+		if !oldDone[i] {
+			name := o.usages[i].name
+			byName[name] = append(byName[name], i)
+		}
+	}
+	for i := range n.usages {
+		if newDone[i] {
+			continue
+		}
+		nu := &n.usages[i]
+		idx := byName[nu.name]
+		if len(idx) == 0 {
+			res = append(res, pair{new: nu})
+			continue
+		}
+		j := idx[0]
+		byName[nu.name] = idx[1:]
+		oldDone[j] = true
+		res = append(res, pair{old: &o.usages[j], new: nu})
+	}
+
+	for i := range o.usages {
+		if !oldDone[i] {
+			res = append(res, pair{old: &o.usages[i]})
+		}
+	}
+	return res
+}
+
+// Write saves changes to the path. Files with ".json" extension get the
+// whole result as JSON, all other files get changes as TSV.
+func (r *Result) Write(path string) error {
+	if strings.HasSuffix(strings.ToLower(path), ".json") {
+		bs, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, append(bs, '\n'), 0644)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Comma = '\t'
+	err = w.Write([]string{"Kind", "ID", "Name", "Old", "New"})
+	if err != nil {
+		return err
+	}
+	for _, v := range r.Changes {
+		err = w.Write([]string{string(v.Kind), v.ID, v.Name, v.Old, v.New})
+		if err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	if err = w.Error(); err != nil {
+		return err
+	}
+	return f.Close()
+}
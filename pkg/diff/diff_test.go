@@ -0,0 +1,128 @@
+package diff_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sfborg/harvester/pkg/diff"
+	"github.com/sfborg/sflib"
+	"github.com/stretchr/testify/assert"
+)
+
+func archiveDB(t *testing.T, stmts []string) *sql.DB {
+	arc := sflib.NewSfga()
+	err := arc.Create(t.TempDir())
+	assert.Nil(t, err)
+	db, err := arc.Connect()
+	assert.Nil(t, err)
+	t.Cleanup(func() { arc.Close() })
+	for _, v := range stmts {
+		_, err = db.Exec(v)
+		assert.Nil(t, err, v)
+	}
+	return db
+}
+
+func TestCompare(t *testing.T) {
+	assert := assert.New(t)
+	oldDB := archiveDB(t, []string{
+		`INSERT INTO name (col__id, gn__scientific_name_string,
+		  col__scientific_name, col__authorship, col__rank_id) VALUES
+		  ('n1', 'Aus', 'Aus', '', 'genus'),
+		  ('n2', 'Aus bus L.', 'Aus bus', 'L.', 'species'),
+		  ('n3', 'Aus cus', 'Aus cus', '', 'species'),
+		  ('n4', 'Aus dus', 'Aus dus', '', 'species'),
+		  ('n5', 'Bus', 'Bus', '', 'genus')`,
+		`INSERT INTO taxon (col__id, col__name_id, col__parent_id) VALUES
+		  ('t1', 'n1', ''), ('t2', 'n2', 't1'), ('t3', 'n3', 't1'),
+		  ('t4', 'n4', 't1'), ('t5', 'n5', '')`,
+		`INSERT INTO vernacular (col__taxon_id, col__name, col__language)
+		  VALUES ('t2', 'bee', 'eng')`,
+	})
+	newDB := archiveDB(t, []string{
+		`INSERT INTO name (col__id, gn__scientific_name_string,
+		  col__scientific_name, col__authorship, col__rank_id) VALUES
+		  ('n1', 'Aus', 'Aus', '', 'genus'),
+		  ('n2', 'Aus bus Linn.', 'Aus bus', 'Linn.', 'species'),
+		  ('n3', 'Aus cus', 'Aus cus', '', 'species'),
+		  ('n4', 'Aus dus', 'Aus dus', '', 'subspecies'),
+		  ('n6', 'Cus', 'Cus', '', 'genus')`,
+		`INSERT INTO taxon (col__id, col__name_id, col__parent_id) VALUES
+		  ('t1', 'n1', ''), ('t2', 'n2', 't6'), ('x4', 'n4', 't1'),
+		  ('t6', 'n6', '')`,
+		`INSERT INTO synonym (col__id, col__taxon_id, col__name_id) VALUES
+		  ('t3', 't1', 'n3')`,
+		`INSERT INTO distribution (col__taxon_id, col__area) VALUES
+		  ('t2', 'Europe')`,
+	})
+
+	res, err := diff.Compare(context.Background(), oldDB, newDB)
+	assert.Nil(err)
+
+	tests := []struct {
+		msg   string
+		kind  diff.Kind
+		count int
+	}{
+		{"added", diff.Added, 1},
+		{"removed", diff.Removed, 1},
+		{"status", diff.StatusChanged, 1},
+		{"parent", diff.Reparented, 1},
+		{"rank", diff.RankChanged, 1},
+		{"authorship", diff.AuthorshipChanged, 1},
+		{"vern added", diff.VernacularAdded, 0},
+		{"vern removed", diff.VernacularRemoved, 1},
+		{"distr added", diff.DistributionAdded, 1},
+	}
+	for _, v := range tests {
+		assert.Equal(v.count, res.Counts[v.kind], v.msg)
+	}
+	assert.Equal(8, res.Total())
+	assert.Equal(diff.Change{Kind: diff.Added, ID: "t6", Name: "Cus"},
+		res.Changes[0])
+
+	// usage with a new ID is matched by name
+	for _, v := range res.Changes {
+		if v.Kind == diff.RankChanged {
+			assert.Equal("x4", v.ID)
+			assert.Equal("species", v.Old)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "diff.tsv")
+	err = res.Write(path)
+	assert.Nil(err)
+	bs, err := os.ReadFile(path)
+	assert.Nil(err)
+	lines := strings.Split(strings.TrimSpace(string(bs)), "\n")
+	assert.Equal(9, len(lines))
+	assert.Equal("Kind\tID\tName\tOld\tNew", lines[0])
+}
+
+func TestCompareParentMatchedByName(t *testing.T) {
+	assert := assert.New(t)
+	oldDB := archiveDB(t, []string{
+		`INSERT INTO name (col__id, gn__scientific_name_string,
+		  col__scientific_name, col__rank_id) VALUES
+		  ('n1', 'Aus', 'Aus', 'genus'),
+		  ('n2', 'Aus bus', 'Aus bus', 'species')`,
+		`INSERT INTO taxon (col__id, col__name_id, col__parent_id) VALUES
+		  ('t1', 'n1', ''), ('t2', 'n2', 't1')`,
+	})
+	newDB := archiveDB(t, []string{
+		`INSERT INTO name (col__id, gn__scientific_name_string,
+		  col__scientific_name, col__rank_id) VALUES
+		  ('n1', 'Aus', 'Aus', 'genus'),
+		  ('n2', 'Aus bus', 'Aus bus', 'species')`,
+		`INSERT INTO taxon (col__id, col__name_id, col__parent_id) VALUES
+		  ('x1', 'n1', ''), ('t2', 'n2', 'x1')`,
+	})
+
+	res, err := diff.Compare(context.Background(), oldDB, newDB)
+	assert.Nil(err)
+	assert.Equal(0, res.Total())
+}
@@ -0,0 +1,105 @@
+package diff
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// usage is a taxon or a synonym with its name.
+type usage struct {
+	id         string
+	name       string
+	authorship string
+	rank       string
+	status     string
+	parentID   string
+}
+
+// archive contains name usages of an SFGA and facts attached to taxa.
+type archive struct {
+	usages        []usage
+	byID          map[string]int
+	vernaculars   map[string]map[string]struct{}
+	distributions map[string]map[string]struct{}
+}
+
+const usagesQuery = `
+SELECT t.col__id, n.col__scientific_name, n.col__authorship, n.col__rank_id,
+  'accepted', t.col__parent_id
+  FROM taxon t JOIN name n ON n.col__id = t.col__name_id
+UNION ALL
+SELECT s.col__id, n.col__scientific_name, n.col__authorship, n.col__rank_id,
+  'synonym', s.col__taxon_id
+  FROM synonym s JOIN name n ON n.col__id = s.col__name_id`
+
+func load(ctx context.Context, db *sql.DB) (*archive, error) {
+	res := archive{byID: make(map[string]int)}
+
+	rows, err := db.QueryContext(ctx, usagesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("loading name usages: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u usage
+		var auth, rank, parent sql.NullString
+		err = rows.Scan(&u.id, &u.name, &auth, &rank, &u.status, &parent)
+		if err != nil {
+			return nil, err
+		}
+		u.authorship, u.rank, u.parentID = auth.String, rank.String, parent.String
+		if _, ok := res.byID[u.id]; u.id != "" && !ok {
+			res.byID[u.id] = len(res.usages)
+		}
+		res.usages = append(res.usages, u)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	res.vernaculars, err = loadFacts(ctx, db, `
+SELECT col__taxon_id, col__name, col__language FROM vernacular`)
+	if err != nil {
+		return nil, fmt.Errorf("loading vernaculars: %w", err)
+	}
+
+	res.distributions, err = loadFacts(ctx, db, `
+SELECT col__taxon_id, col__area, col__status_id FROM distribution`)
+	if err != nil {
+		return nil, fmt.Errorf("loading distributions: %w", err)
+	}
+	return &res, nil
+}
+
+// loadFacts reads a query that returns a taxon ID, a value and its
+// qualifier, and groups the values by taxon ID.
+func loadFacts(
+	ctx context.Context,
+	db *sql.DB,
+	q string,
+) (map[string]map[string]struct{}, error) {
+	rows, err := db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make(map[string]map[string]struct{})
+	for rows.Next() {
+		var id, val string
+		var qual sql.NullString
+		if err = rows.Scan(&id, &val, &qual); err != nil {
+			return nil, err
+		}
+		if qual.String != "" {
+			val += " (" + qual.String + ")"
+		}
+		if res[id] == nil {
+			res[id] = make(map[string]struct{})
+		}
+		res[id][val] = struct{}{}
+	}
+	return res, rows.Err()
+}
@@ -15,6 +15,12 @@ const (
 
 	// Wikisp
 	WikispSkipPage
+
+	// Validation
+	ValidationError
+
+	// Export
+	ExportError
 )
 
 func Is(err error, code gn.ErrorCode) bool {
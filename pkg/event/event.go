@@ -0,0 +1,99 @@
+// Package event describes progress of harvests for observers, like the
+// command line interface or a web service that embeds harvester.
+package event
+
+import "time"
+
+// Type is the kind of an event.
+type Type int
+
+const (
+	// StageStarted is sent when a stage of a harvest (download, extract,
+	// init, convert, validate, export) starts.
+	StageStarted Type = iota
+
+	// StageFinished is sent when a stage of a harvest ends. Duration is the
+	// time the stage took, Err is the error of the stage, if any.
+	StageFinished
+
+	// Processed reports the number of records processed so far. Unit
+	// describes records, for example "names" or "lines".
+	Processed
+
+	// Downloaded reports the number of bytes downloaded so far. Total is
+	// the size of the file, or -1 if it is unknown.
+	Downloaded
+
+	// Warning reports a problem that does not stop the harvest.
+	Warning
+
+	// Rejected reports Count records rejected for the reason in Message.
+	Rejected
+
+	// Info reports a progress message, for example about a step of a
+	// conversion. Message can contain markup tags of gn package, like <em>.
+	Info
+)
+
+var typeNames = []string{
+	"stage-started", "stage-finished", "processed", "downloaded", "warning",
+	"rejected", "info",
+}
+
+// String returns the name of the event type.
+func (t Type) String() string {
+	if int(t) < 0 || int(t) >= len(typeNames) {
+		return "unknown"
+	}
+	return typeNames[t]
+}
+
+// Event contains information about progress of a harvest. Fields that are
+// not relevant to the Type are empty.
+type Event struct {
+	Type Type
+
+	// Source is the label of the data source.
+	Source string
+
+	// Stage is the name of the stage for StageStarted and StageFinished.
+	Stage string
+
+	// Count is the number of processed records, downloaded bytes or
+	// rejected records.
+	Count int64
+
+	// Total is the expected Count, if it is known.
+	Total int64
+
+	// Unit describes what is counted by Processed events.
+	Unit string
+
+	// Done is true for the last Processed event of a stage.
+	Done bool
+
+	// Message is the text of an info message or a warning, the reason of
+	// a rejection, or the URL of a download.
+	Message string
+
+	// Duration is the time a stage took, or the estimated time left of a
+	// download.
+	Duration time.Duration
+
+	// Err is the error of a failed stage.
+	Err error
+}
+
+// Observer receives events of harvests. Data sources can be harvested in
+// parallel, so Notify must be safe for concurrent use.
+type Observer interface {
+	Notify(Event)
+}
+
+// ObserverFunc allows to use a function as an Observer.
+type ObserverFunc func(Event)
+
+// Notify calls f(e).
+func (f ObserverFunc) Notify(e Event) {
+	f(e)
+}
@@ -1,14 +1,19 @@
 package harvester
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
 
 	"github.com/gnames/gn"
 	"github.com/sfborg/harvester/internal/list"
+	"github.com/sfborg/harvester/internal/sysio"
 	"github.com/sfborg/harvester/pkg/config"
 	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/harvester/pkg/errcode"
+	"github.com/sfborg/harvester/pkg/validate"
 	"github.com/sfborg/sflib/pkg/sfga"
 )
 
@@ -19,20 +24,41 @@ type harvester struct {
 	sfga   sfga.Archive
 }
 
-func New(cfg config.Config) Harvester {
+// New creates a Harvester with built-in data sources, data sources added
+// by Register and the extra ones. It returns an error if labels of data
+// sources collide.
+func New(cfg config.Config, extra ...Factory) (Harvester, error) {
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = UserAgent()
+	}
 	res := harvester{
 		cfg: cfg,
 		ds:  list.GetDataSets(cfg),
 	}
 
-	return &res
+	err := addDataSets(cfg, res.ds, append(registered(), extra...))
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
 }
 
 func (h *harvester) List() map[string]data.Convertor {
 	return h.ds
 }
 
-func (h *harvester) Get(label, outPath string) error {
+// Get harvests a data source to SFGA files at outPath and returns paths of
+// the files with statistics of the harvest. A harvest report is saved next
+// to them (see ReportPath), as well as rejected records, if there are any
+// (see RejectedPath). Files are written to a temporary location
+// first, so a failed export never leaves truncated files at outPath. If the
+// input did not change since the last successful harvest, conversion is
+// skipped and ErrUnchanged is returned, unless WithForce is set. Extracted
+// files of the previous harvest are kept in that case.
+func (h *harvester) Get(
+	ctx context.Context,
+	label, outPath string,
+) (*Result, error) {
 	var err error
 	var sfga sfga.Archive
 	var ds data.Convertor
@@ -40,38 +66,178 @@ func (h *harvester) Get(label, outPath string) error {
 	var dlPath string
 	if ds, ok = h.ds[label]; !ok {
 		err = fmt.Errorf("Label '%s' does not exist", label)
-		return err
+		return nil, err
 	}
 
+	rep := ds.Report()
+	rep.Reset()
+	rep.OutputPath = outPath
+
 	if h.cfg.SkipDownload {
 		slog.Info("skip download step", "source", ds.Label())
-		gn.Message("Skipping download for <em>%s</em>", ds.Label())
+		ds.Config().Info("Skipping download for <em>%s</em>", ds.Label())
 	} else {
-		dlPath, err = ds.Download()
-		if err != nil {
+		err = timed(ds, "download", func() error {
+			dlPath, err = ds.Download(ctx)
 			return err
+		})
+		if err != nil {
+			return nil, h.interrupted(ds, "download", nil, err)
+		}
+	}
+
+	input := dlPath
+	if input == "" {
+		input = ds.Config().LoadFile
+	}
+	if err = setInput(rep, input); err != nil {
+		slog.Warn("cannot calculate input checksum", "error", err)
+	}
+
+	if !h.cfg.WithForce && isUnchanged(ds.Config(), rep) {
+		slog.Info("input is unchanged, skip conversion", "source", ds.Label())
+		ds.Config().Info(
+			"Input of <em>%s</em> did not change since the last harvest to "+
+				"<em>%s</em>, skipping conversion (use --force to convert anyway)",
+			ds.Label(), outPath,
+		)
+		return nil, ErrUnchanged
+	}
+
+	// the cache is reset only now, so a skipped harvest keeps files of
+	// the previous one for --skip-download.
+	if dlPath != "" {
+		if err = sysio.ResetExtract(ds.Config()); err != nil {
+			return nil, err
 		}
 	}
 
+	q, err := openQuarantine(outPath)
+	if err != nil {
+		return nil, err
+	}
+	defer q.discard()
+	rep.SetQuarantine(q.Quarantine)
+
 	slog.Info("extracting files", "source", ds.Label())
-	gn.Message("Extracting files of <em>%s</em>", ds.Label())
-	err = ds.Extract(dlPath)
+	err = timed(ds, "extract", func() error {
+		return ds.Extract(ctx, dlPath)
+	})
 	if err != nil {
-		return err
+		return nil, h.interrupted(ds, "extract", nil, err)
 	}
 
 	slog.Info("creating SFG archive")
-	gn.Message("Creating empty SFGA file")
-	sfga, err = ds.InitSfga()
+	err = timed(ds, "init", func() error {
+		sfga, err = ds.InitSfga(ctx)
+		return err
+	})
 	if err != nil {
+		return nil, h.interrupted(ds, "init", nil, err)
+	}
+	defer sfga.Close()
+
+	err = timed(ds, "convert", func() error {
+		return ds.ToSfga(ctx, sfga)
+	})
+	if err != nil {
+		return nil, h.interrupted(ds, "convert", sfga, err)
+	}
+
+	if cfg := ds.Config(); cfg.IsPartial() {
+		err = timed(ds, "prune", func() error {
+			return pruneArchive(ctx, sfga, cfg.Root)
+		})
+		if err != nil {
+			return nil, h.interrupted(ds, "prune", sfga, err)
+		}
+	}
+
+	if err = archiveMeta(sfga, rep); err != nil {
+		slog.Warn("cannot read archive metadata", "error", err)
+	}
+	if err = archiveStats(sfga, rep); err != nil {
+		slog.Warn("cannot collect archive statistics", "error", err)
+	}
+
+	var vRep *validate.Report
+	if ds.Config().WithValidation {
+		err = timed(ds, "validate", func() error {
+			vRep, err = validateArchive(ctx, ds.Config(), sfga, rep)
+			return err
+		})
+		if err != nil {
+			return nil, h.interrupted(ds, "validate", sfga, err)
+		}
+	}
+
+	var files []string
+	err = timed(ds, "export", func() error {
+		files, err = export(sfga, outPath, ds.Config().WithZipOutput)
 		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	err = ds.ToSfga(sfga)
+	rejectedPath, err := q.save(outPath)
 	if err != nil {
+		return nil, err
+	}
+
+	reportPath := ReportPath(outPath)
+	if err = rep.Write(reportPath); err != nil {
+		return nil, err
+	}
+	slog.Info("harvest report is created", "file", reportPath)
+	ds.Config().Info("Harvest report is saved to <em>%s</em>", reportPath)
+
+	res := newResult(rep, files, rejectedPath)
+	if vRep != nil && !vRep.OK() {
+		return res, &gn.Error{
+			Code: errcode.ValidationError,
+			Msg:  "Archive of <em>%s</em> has %d integrity issues",
+			Vars: []any{ds.Label(), vRep.Total()},
+			Err: fmt.Errorf(
+				"archive of %s has %d integrity issues", ds.Label(), vRep.Total(),
+			),
+		}
+	}
+
+	if err = saveLastHarvest(ds.Config(), rep); err != nil {
+		slog.Warn("cannot save harvest state", "source", ds.Label(), "error", err)
+	}
+	return res, nil
+}
+
+// interrupted reports what is left in the cache when a stage of the
+// harvest was canceled. A partially built SFGA is closed and removed,
+// downloaded and extracted files are kept so the harvest can be repeated
+// with --skip-download. For errors other than cancellation it returns the
+// error unchanged.
+func (h *harvester) interrupted(
+	ds data.Convertor,
+	stage string,
+	arc sfga.Archive,
+	err error,
+) error {
+	if !errors.Is(err, context.Canceled) &&
+		!errors.Is(err, context.DeadlineExceeded) {
 		return err
 	}
 
-	sfga.Export(outPath, ds.Config().WithZipOutput)
-	return nil
+	cfg := ds.Config()
+	if arc != nil {
+		arc.Close()
+	}
+	if rmErr := sysio.EmptyDir(cfg.SfgaDir); rmErr != nil {
+		slog.Warn("cannot remove partial SFGA", "dir", cfg.SfgaDir, "error", rmErr)
+	}
+
+	slog.Warn("harvest interrupted", "source", ds.Label(), "stage", stage)
+	cfg.Warn("Harvest interrupted at %s stage", stage)
+	cfg.Info("Partial SFGA was removed, no output was written")
+	cfg.Info("Downloaded files are kept in %s", cfg.DownloadDir)
+	cfg.Info("Extracted files are kept in %s", cfg.ExtractDir)
+	return err
 }
@@ -1,8 +1,18 @@
 package harvester
 
-import "github.com/sfborg/harvester/pkg/data"
+import (
+	"context"
+
+	"github.com/sfborg/harvester/pkg/data"
+)
 
 type Harvester interface {
 	List() map[string]data.Convertor
-	Get(datasetLabel, outPath string) error
+
+	// Get harvests a data source to SFGA files at outPath.
+	Get(ctx context.Context, datasetLabel, outPath string) (*Result, error)
+
+	// GetBatch harvests several data sources in parallel and reports the
+	// outcome for each of them.
+	GetBatch(ctx context.Context, tasks []Task) []TaskResult
 }
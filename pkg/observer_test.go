@@ -0,0 +1,91 @@
+package harvester_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	harvester "github.com/sfborg/harvester/pkg"
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/sfborg/harvester/pkg/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserverEvents(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "owls.txt")
+	list := "Bubo bubo (Linnaeus, 1758)\nStrix aluco Linnaeus, 1758\n"
+	assert.Nil(os.WriteFile(path, []byte(list), 0644))
+
+	var mu sync.Mutex
+	var events []event.Event
+	obs := event.ObserverFunc(func(e event.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, e)
+	})
+	cfg := config.New(
+		config.OptCacheDir(filepath.Join(dir, "cache")),
+		config.OptLocalFile(path),
+		config.OptObserver(obs),
+	)
+	hr, err := harvester.New(cfg)
+	assert.Nil(err)
+
+	stderr := captureStderr(t, func() {
+		_, err = hr.Get(context.Background(), "names", filepath.Join(dir, "owls"))
+	})
+	assert.Nil(err)
+	assert.Empty(stderr, "messages go to the observer only")
+
+	var res []string
+	for _, v := range events {
+		assert.Equal("names", v.Source)
+		switch v.Type {
+		case event.StageStarted, event.StageFinished:
+			res = append(res, v.Type.String()+" "+v.Stage)
+		case event.Info:
+			res = append(res, v.Message)
+		}
+	}
+	assert.Equal([]string{
+		"stage-started download",
+		"Using local file for names: " + path,
+		"stage-finished download",
+		"stage-started extract",
+		"Copying names file",
+		"stage-finished extract",
+		"stage-started init",
+		"stage-finished init",
+		"stage-started convert",
+		"Importing Meta",
+		"Importing Name Usages",
+		"stage-finished convert",
+		"stage-started export",
+		"stage-finished export",
+		"Harvest report is saved to <em>" +
+			harvester.ReportPath(filepath.Join(dir, "owls")) + "</em>",
+	}, res)
+}
+
+// captureStderr returns everything f writes to os.Stderr.
+func captureStderr(t *testing.T, f func()) string {
+	r, w, err := os.Pipe()
+	assert.Nil(t, err)
+	stderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = stderr }()
+
+	ch := make(chan string)
+	go func() {
+		bs, _ := io.ReadAll(r)
+		ch <- string(bs)
+	}()
+	f()
+	w.Close()
+	return <-ch
+}
@@ -0,0 +1,59 @@
+package harvester
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/sfborg/harvester/pkg/data"
+)
+
+// RejectedPath returns the path of the file with rejected records for an
+// output path.
+func RejectedPath(outPath string) string {
+	return outPath + ".rejected.tsv"
+}
+
+// quarantine keeps rejected records of a harvest in a temporary file next
+// to the output, until the harvest succeeds.
+type quarantine struct {
+	*data.Quarantine
+	tmpPath string
+}
+
+// openQuarantine creates the temporary file. It fails if outPath is not
+// writable, so it is called before the conversion starts.
+func openQuarantine(outPath string) (*quarantine, error) {
+	dir, base := filepath.Split(outPath)
+	tmpPath := filepath.Join(dir, "."+base+".rejected.tsv.tmp")
+	q, err := data.NewQuarantine(tmpPath)
+	if err != nil {
+		return nil, exportError(outPath, err)
+	}
+	return &quarantine{Quarantine: q, tmpPath: tmpPath}, nil
+}
+
+// save moves the file to RejectedPath and returns its path. If no records
+// were rejected, the file and the one from a previous harvest are removed.
+func (q *quarantine) save(outPath string) (string, error) {
+	if err := q.Close(); err != nil {
+		return "", err
+	}
+	path := RejectedPath(outPath)
+	if q.Len() == 0 {
+		os.Remove(q.tmpPath)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+		return "", nil
+	}
+	if err := os.Rename(q.tmpPath, path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// discard removes the temporary file, if it was not saved.
+func (q *quarantine) discard() {
+	q.Close()
+	os.Remove(q.tmpPath)
+}
@@ -0,0 +1,59 @@
+package harvester
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/sfborg/harvester/pkg/data"
+)
+
+// Factory creates a Convertor of a data source out of configuration.
+type Factory func(cfg config.Config) data.Convertor
+
+var registry struct {
+	sync.Mutex
+	factories []Factory
+}
+
+// Register adds a data source to every Harvester created afterwards. It
+// allows Go modules outside of harvester to provide their own data sources,
+// usually from an init function. Labels of data sources are checked for
+// collisions by New.
+func Register(f Factory) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.factories = append(registry.factories, f)
+}
+
+// registered returns factories added by Register.
+func registered() []Factory {
+	registry.Lock()
+	defer registry.Unlock()
+	return append([]Factory(nil), registry.factories...)
+}
+
+// addDataSets creates convertors out of factories and adds them to ds. It
+// returns an error if a label is empty or already taken.
+func addDataSets(
+	cfg config.Config,
+	ds map[string]data.Convertor,
+	fs []Factory,
+) error {
+	for _, f := range fs {
+		c := f(cfg)
+		label := c.Label()
+		if label == "" {
+			return fmt.Errorf("data source '%s' has an empty label", c.Name())
+		}
+		if slices.Contains(config.ReservedLabels, label) {
+			return fmt.Errorf("data source label '%s' is reserved", label)
+		}
+		if _, ok := ds[label]; ok {
+			return fmt.Errorf("data source label '%s' is already taken", label)
+		}
+		ds[label] = c
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package harvester_test
+
+import (
+	"testing"
+
+	harvester "github.com/sfborg/harvester/pkg"
+	"github.com/sfborg/harvester/pkg/base"
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/sfborg/harvester/pkg/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func herbarium(cfg config.Config) data.Convertor {
+	set := data.DataSet{Label: "herbarium", Name: "Internal herbarium"}
+	return base.New(cfg, &set)
+}
+
+func TestRegister(t *testing.T) {
+	assert := assert.New(t)
+	cfg := config.New(config.OptCacheDir(t.TempDir()))
+
+	hr, err := harvester.New(cfg)
+	assert.Nil(err)
+	assert.NotContains(hr.List(), "herbarium")
+	assert.Contains(hr.List(), "itis")
+
+	hr, err = harvester.New(cfg, herbarium)
+	assert.Nil(err)
+	assert.Contains(hr.List(), "herbarium")
+	assert.Equal("Internal herbarium", hr.List()["herbarium"].Name())
+
+	harvester.Register(herbarium)
+	hr, err = harvester.New(cfg)
+	assert.Nil(err)
+	assert.Contains(hr.List(), "herbarium")
+
+	_, err = harvester.New(cfg, herbarium)
+	assert.ErrorContains(err, "'herbarium' is already taken")
+
+	itis := func(cfg config.Config) data.Convertor {
+		set := data.DataSet{Label: "itis", Name: "Another ITIS"}
+		return base.New(cfg, &set)
+	}
+	_, err = harvester.New(cfg, itis)
+	assert.ErrorContains(err, "'itis' is already taken")
+
+	for _, label := range config.ReservedLabels {
+		reserved := func(cfg config.Config) data.Convertor {
+			set := data.DataSet{Label: label, Name: "Reserved"}
+			return base.New(cfg, &set)
+		}
+		_, err = harvester.New(cfg, reserved)
+		assert.ErrorContains(err, "'"+label+"' is reserved")
+	}
+}
@@ -0,0 +1,173 @@
+package harvester
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/sfborg/harvester/internal/fetch"
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/harvester/pkg/event"
+	"github.com/sfborg/harvester/pkg/validate"
+	"github.com/sfborg/sflib/pkg/sfga"
+)
+
+// reportTables are SFGA tables with data records.
+var reportTables = []string{
+	"author", "reference", "name", "taxon", "synonym", "vernacular",
+	"name_relation", "type_material", "distribution", "media", "treatment",
+	"species_estimate", "taxon_property", "species_interaction",
+	"taxon_concept_relation",
+}
+
+// ReportPath returns the path of the harvest report for an output path.
+func ReportPath(outPath string) string {
+	return outPath + ".report.json"
+}
+
+// timed runs a stage of a harvest, records its duration in the report and
+// notifies the observer about the start and the end of the stage.
+func timed(ds data.Convertor, stage string, f func() error) error {
+	cfg := ds.Config()
+	cfg.Notify(event.Event{Type: event.StageStarted, Stage: stage})
+	start := time.Now()
+	err := f()
+	dur := time.Since(start)
+	ds.Report().AddStage(stage, dur)
+	cfg.Notify(event.Event{
+		Type: event.StageFinished, Stage: stage, Duration: dur, Err: err,
+	})
+	return err
+}
+
+// setInput records the input file of a harvest and its checksum, unless
+// the download store already did it. Inputs that are not local files
+// (URLs, directories) are recorded without a checksum.
+func setInput(rep *data.HarvestReport, path string) error {
+	if path == "" || rep.InputSHA256 != "" {
+		return nil
+	}
+	rep.InputFile = path
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return nil
+	}
+
+	sum, size, err := fetch.Checksum(path)
+	if err != nil {
+		return err
+	}
+	rep.InputSHA256, rep.InputSize = sum, size
+	return nil
+}
+
+// archiveMeta adds the version and the issue date of the data from
+// metadata of the archive to the report.
+func archiveMeta(arc sfga.Archive, rep *data.HarvestReport) error {
+	meta, err := arc.LoadMeta()
+	if err != nil || meta == nil {
+		return err
+	}
+	rep.Version, rep.Issued = meta.Version, meta.Issued
+	return nil
+}
+
+// archiveStats adds counts of records and a histogram of parsing quality
+// of names from the archive to the report.
+func archiveStats(arc sfga.Archive, rep *data.HarvestReport) error {
+	db, err := arc.Connect()
+	if err != nil {
+		return err
+	}
+
+	for _, v := range reportTables {
+		var num int
+		q := fmt.Sprintf("SELECT count(*) FROM %s", v)
+		if err = db.QueryRow(q).Scan(&num); err != nil {
+			return err
+		}
+		rep.Tables[v] = num
+	}
+
+	q := `SELECT gn__parse_quality, count(*) FROM name
+	GROUP BY gn__parse_quality`
+	rows, err := db.Query(q)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var quality sql.NullInt64
+		var num int
+		if err = rows.Scan(&quality, &num); err != nil {
+			return err
+		}
+		key := "none"
+		if quality.Valid {
+			key = strconv.Itoa(int(quality.Int64))
+		}
+		rep.ParseQuality[key] = num
+	}
+	return rows.Err()
+}
+
+// pruneArchive removes records that are outside of a partial harvest (see
+// config.Config.IsPartial). If root is given, only its subtree is kept.
+func pruneArchive(ctx context.Context, arc sfga.Archive, root string) error {
+	db, err := arc.Connect()
+	if err != nil {
+		return err
+	}
+
+	var res map[string]int
+	if root != "" {
+		res, err = validate.Subtree(ctx, db, root)
+	} else {
+		res, err = validate.Prune(ctx, db)
+	}
+	if err != nil {
+		return err
+	}
+	for k, v := range res {
+		slog.Info("pruned records of partial harvest", "table", k, "count", v)
+	}
+	return nil
+}
+
+// validateArchive checks referential integrity of the archive and adds
+// the number of issues to the report.
+func validateArchive(
+	ctx context.Context,
+	cfg config.Config,
+	arc sfga.Archive,
+	rep *data.HarvestReport,
+) (*validate.Report, error) {
+	slog.Info("validating archive")
+	db, err := arc.Connect()
+	if err != nil {
+		return nil, err
+	}
+	res, err := validate.Check(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	res.Log()
+	if res.OK() {
+		cfg.Info("No integrity issues found")
+	}
+	for _, v := range res.Categories() {
+		cfg.Warn("Found %d issues: <em>%s</em>", res.Counts[v], v)
+	}
+
+	rep.Validation = make(map[string]int)
+	for k, v := range res.Counts {
+		rep.Validation[string(k)] = v
+	}
+	return res, nil
+}
@@ -0,0 +1,117 @@
+package harvester
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/gnames/gn"
+	"github.com/sfborg/harvester/pkg/data"
+	"github.com/sfborg/harvester/pkg/errcode"
+	"github.com/sfborg/sflib/pkg/sfga"
+)
+
+// Result describes a harvest of a data source done by Get.
+type Result struct {
+	// Label of the data source.
+	Label string
+
+	// Files are paths of the created SFGA files: SQL dump, SQLite database
+	// and, if WithZipOutput is set, their zip archives.
+	Files []string
+
+	// ReportPath is the path of the harvest report.
+	ReportPath string
+
+	// RejectedPath is the path of the file with rejected records. It is
+	// empty if no records were rejected.
+	RejectedPath string
+
+	// Version of the data given by the data source, if it is known.
+	Version string
+
+	// Issued is the date the data were published, if it is known.
+	Issued string
+
+	// Tables contain the number of records in SFGA tables.
+	Tables map[string]int
+
+	// Rejected contains the number of rejected records by reason.
+	Rejected map[string]int
+
+	// Stages contain durations of harvest stages in the order they ran.
+	Stages []data.StageReport
+
+	// Duration is the time the whole harvest took.
+	Duration time.Duration
+}
+
+// newResult creates a Result out of a harvest report.
+func newResult(
+	rep *data.HarvestReport,
+	files []string,
+	rejectedPath string,
+) *Result {
+	return &Result{
+		Label:        rep.Label,
+		Files:        files,
+		ReportPath:   ReportPath(rep.OutputPath),
+		RejectedPath: rejectedPath,
+		Version:      rep.Version,
+		Issued:       rep.Issued,
+		Tables:       maps.Clone(rep.Tables),
+		Rejected:     maps.Clone(rep.Rejected),
+		Stages:       slices.Clone(rep.Stages),
+		Duration:     time.Since(rep.StartedAt),
+	}
+}
+
+// exportFiles returns paths of files created by Export of SFGA.
+func exportFiles(outPath string, withZip bool) []string {
+	res := []string{outPath + ".sql", outPath + ".sqlite"}
+	if withZip {
+		res = append(res, outPath+".sql.zip", outPath+".sqlite.zip")
+	}
+	return res
+}
+
+// export saves the archive to outPath. Files are created in a temporary
+// directory next to outPath and moved to outPath only when all of them are
+// ready, so a failed export never leaves truncated files there. Names of
+// the files stay the same, because zip archives keep them inside.
+func export(arc sfga.Archive, outPath string, withZip bool) ([]string, error) {
+	dir, base := filepath.Split(outPath)
+	if dir == "" {
+		dir = "."
+	}
+	tmpDir, err := os.MkdirTemp(dir, "."+base+".tmp-")
+	if err != nil {
+		return nil, exportError(outPath, err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err = arc.Export(filepath.Join(tmpDir, base), withZip); err != nil {
+		return nil, exportError(outPath, err)
+	}
+
+	res := exportFiles(outPath, withZip)
+	for _, v := range res {
+		src := filepath.Join(tmpDir, filepath.Base(v))
+		if err = os.Rename(src, v); err != nil {
+			return nil, exportError(outPath, err)
+		}
+	}
+	return res, nil
+}
+
+func exportError(outPath string, err error) error {
+	return &gn.Error{
+		Code: errcode.ExportError,
+		Msg:  "Cannot export SFGA to <em>%s</em>",
+		Vars: []any{outPath},
+		Err:  fmt.Errorf("cannot export SFGA to %s: %w", outPath, err),
+	}
+}
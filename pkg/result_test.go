@@ -0,0 +1,79 @@
+package harvester_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	harvester "github.com/sfborg/harvester/pkg"
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/sfborg/harvester/pkg/errcode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetResult(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "owls.txt")
+	list := "Bubo bubo (Linnaeus, 1758)\nStrix aluco Linnaeus, 1758\n" +
+		"Bubo bubo (Linnaeus, 1758)\n"
+	assert.Nil(os.WriteFile(path, []byte(list), 0644))
+
+	cfg := config.New(
+		config.OptCacheDir(filepath.Join(dir, "cache")),
+		config.OptLocalFile(path),
+		config.OptArchiveVersion("v1"),
+		config.OptWithZipOutput(true),
+	)
+	hr, err := harvester.New(cfg)
+	assert.Nil(err)
+
+	out := filepath.Join(dir, "out", "owls")
+	_, err = hr.Get(context.Background(), "names", out)
+	assert.True(errcode.Is(err, errcode.ExportError))
+	_, err = os.Stat(filepath.Dir(out))
+	assert.True(os.IsNotExist(err))
+
+	assert.Nil(os.Mkdir(filepath.Dir(out), 0755))
+	res, err := hr.Get(context.Background(), "names", out)
+	assert.Nil(err)
+	assert.Equal("names", res.Label)
+	assert.Equal("v1", res.Version)
+	assert.Equal(2, res.Tables["name"])
+	assert.Equal(harvester.ReportPath(out), res.ReportPath)
+	assert.Equal([]string{
+		out + ".sql", out + ".sqlite", out + ".sql.zip", out + ".sqlite.zip",
+	}, res.Files)
+	assert.Positive(res.Duration)
+	assert.Equal(1, res.Rejected["duplicate name"])
+	assert.Equal(harvester.RejectedPath(out), res.RejectedPath)
+
+	rejected, err := os.ReadFile(res.RejectedPath)
+	assert.Nil(err)
+	assert.Equal(
+		"location\treason\traw\nline 3\tduplicate name\tBubo bubo (Linnaeus, 1758)\n",
+		string(rejected),
+	)
+
+	files, err := os.ReadDir(filepath.Dir(out))
+	assert.Nil(err)
+	var names []string
+	for _, v := range files {
+		names = append(names, v.Name())
+	}
+	assert.Equal([]string{
+		"owls.rejected.tsv", "owls.report.json", "owls.sql", "owls.sql.zip",
+		"owls.sqlite", "owls.sqlite.zip",
+	}, names)
+
+	// rejected records of a previous harvest are removed.
+	list = list[:strings.LastIndex(list, "Bubo")]
+	assert.Nil(os.WriteFile(path, []byte(list), 0644))
+	res, err = hr.Get(context.Background(), "names", out)
+	assert.Nil(err)
+	assert.Empty(res.RejectedPath)
+	_, err = os.Stat(harvester.RejectedPath(out))
+	assert.True(os.IsNotExist(err))
+}
@@ -0,0 +1,124 @@
+package harvester
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/sfborg/harvester/pkg/data"
+)
+
+// ErrUnchanged is returned by Get when the input of a data source is
+// byte-identical to the input of its last successful harvest, and
+// the harvest is not forced.
+var ErrUnchanged = errors.New("input did not change since the last harvest")
+
+// lastHarvest describes the last successful harvest of a data source.
+type lastHarvest struct {
+	InputSHA256 string    `json:"inputSha256"`
+	InputSize   int64     `json:"inputSize"`
+	OutputPath  string    `json:"outputPath"`
+	Fingerprint string    `json:"fingerprint"`
+	FinishedAt  time.Time `json:"finishedAt"`
+}
+
+// fingerprint returns a hash of the harvester version and of the settings
+// that change the output of a harvest. If any of them changes, the
+// harvest is not skipped.
+func fingerprint(cfg config.Config) string {
+	settings := struct {
+		Version        string
+		Code           string
+		ColumnsFile    string
+		Columns        string
+		InferGenera    bool
+		NewTaxdump     bool
+		ColSep         string
+		WithoutQuotes  bool
+		BadRow         int
+		ArchiveVersion string
+		WithZipOutput  bool
+		Schema         string
+	}{
+		Version:        Version,
+		Code:           cfg.Code.String(),
+		ColumnsFile:    cfg.ColumnsFile,
+		InferGenera:    cfg.InferGenera,
+		NewTaxdump:     cfg.NewTaxdump,
+		ColSep:         cfg.ColSep,
+		WithoutQuotes:  cfg.WithoutQuotes,
+		BadRow:         int(cfg.BadRow),
+		ArchiveVersion: cfg.ArchiveVersion,
+		WithZipOutput:  cfg.WithZipOutput,
+		Schema:         cfg.LocalSchemaPath,
+	}
+	// the mapping of columns can change while the file keeps its name.
+	if cfg.ColumnsFile != "" {
+		if bs, err := os.ReadFile(cfg.ColumnsFile); err == nil {
+			sum := sha256.Sum256(bs)
+			settings.Columns = hex.EncodeToString(sum[:])
+		}
+	}
+	bs, _ := json.Marshal(settings)
+	sum := sha256.Sum256(bs)
+	return hex.EncodeToString(sum[:])
+}
+
+func lastHarvestPath(cfg config.Config) string {
+	return filepath.Join(cfg.StoreDir(), "harvests", cfg.Label+".json")
+}
+
+// isUnchanged returns true if the input of the report is the same as the
+// input of the last successful harvest to the same output with the same
+// harvester version and settings, and that output still exists. Partial
+// harvests are never skipped.
+func isUnchanged(cfg config.Config, rep *data.HarvestReport) bool {
+	if rep.InputSHA256 == "" || cfg.IsPartial() {
+		return false
+	}
+	bs, err := os.ReadFile(lastHarvestPath(cfg))
+	if err != nil {
+		return false
+	}
+	var last lastHarvest
+	if err = json.Unmarshal(bs, &last); err != nil {
+		return false
+	}
+	if last.InputSHA256 != rep.InputSHA256 ||
+		last.InputSize != rep.InputSize ||
+		last.OutputPath != rep.OutputPath ||
+		last.Fingerprint != fingerprint(cfg) {
+		return false
+	}
+	files, _ := filepath.Glob(rep.OutputPath + ".sql*")
+	return len(files) > 0
+}
+
+// saveLastHarvest records the input of a successful harvest. Partial
+// harvests are not recorded, so they do not prevent a full one.
+func saveLastHarvest(cfg config.Config, rep *data.HarvestReport) error {
+	if rep.InputSHA256 == "" || cfg.IsPartial() {
+		return nil
+	}
+	last := lastHarvest{
+		InputSHA256: rep.InputSHA256,
+		InputSize:   rep.InputSize,
+		OutputPath:  rep.OutputPath,
+		Fingerprint: fingerprint(cfg),
+		FinishedAt:  time.Now(),
+	}
+	bs, err := json.MarshalIndent(last, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := lastHarvestPath(cfg)
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, bs, 0644)
+}
@@ -0,0 +1,50 @@
+package harvester_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	harvester "github.com/sfborg/harvester/pkg"
+	"github.com/sfborg/harvester/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUnchanged(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "owls.txt")
+	list := "Bubo bubo (Linnaeus, 1758)\nStrix aluco Linnaeus, 1758\n"
+	assert.Nil(os.WriteFile(path, []byte(list), 0644))
+	out := filepath.Join(dir, "owls")
+
+	get := func(opts ...config.Option) error {
+		opts = append([]config.Option{
+			config.OptCacheDir(filepath.Join(dir, "cache")),
+			config.OptLocalFile(path),
+		}, opts...)
+		hr, err := harvester.New(config.New(opts...))
+		assert.Nil(err)
+		_, err = hr.Get(context.Background(), "names", out)
+		return err
+	}
+
+	assert.Nil(get())
+	// a skipped harvest keeps extracted files of the previous one.
+	extractDir := config.New(
+		config.OptCacheDir(filepath.Join(dir, "cache")),
+	).ForLabel("names").ExtractDir
+	marker := filepath.Join(extractDir, "marker")
+	assert.Nil(os.WriteFile(marker, nil, 0644))
+	assert.ErrorIs(get(), harvester.ErrUnchanged)
+	assert.FileExists(marker)
+	assert.FileExists(filepath.Join(extractDir, "owls.txt"))
+
+	// settings that change the output make a new harvest.
+	assert.Nil(get(config.OptWithZipOutput(true)))
+	assert.FileExists(out + ".sqlite.zip")
+	assert.NoFileExists(marker)
+	assert.ErrorIs(get(config.OptWithZipOutput(true)), harvester.ErrUnchanged)
+	assert.Nil(get(config.OptWithZipOutput(true), config.OptInferGenera(true)))
+}
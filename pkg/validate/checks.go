@@ -0,0 +1,248 @@
+package validate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// queryCheck finds issues with an SQL query that returns ID of a record
+// and the value that cannot be resolved.
+type queryCheck struct {
+	category Category
+	table    string
+	query    string
+}
+
+var queryChecks = []queryCheck{
+	{DanglingParent, "taxon", `
+SELECT t.col__id, t.col__parent_id FROM taxon t
+  WHERE t.col__parent_id != ''
+    AND NOT EXISTS (SELECT 1 FROM taxon p WHERE p.col__id = t.col__parent_id)
+    AND NOT EXISTS (SELECT 1 FROM synonym s WHERE s.col__id = t.col__parent_id)`,
+	},
+	{ParentIsSynonym, "taxon", `
+SELECT t.col__id, t.col__parent_id FROM taxon t
+  WHERE t.col__parent_id != ''
+    AND NOT EXISTS (SELECT 1 FROM taxon p WHERE p.col__id = t.col__parent_id)
+    AND EXISTS (SELECT 1 FROM synonym s WHERE s.col__id = t.col__parent_id)`,
+	},
+	{SynonymOfSynonym, "synonym", `
+SELECT s.col__id, s.col__taxon_id FROM synonym s
+  WHERE NOT EXISTS (SELECT 1 FROM taxon t WHERE t.col__id = s.col__taxon_id)
+    AND EXISTS (SELECT 1 FROM synonym s2 WHERE s2.col__id = s.col__taxon_id)`,
+	},
+	{MissingTaxon, "synonym", `
+SELECT s.col__id, s.col__taxon_id FROM synonym s
+  WHERE NOT EXISTS (SELECT 1 FROM taxon t WHERE t.col__id = s.col__taxon_id)
+    AND NOT EXISTS (SELECT 1 FROM synonym s2 WHERE s2.col__id = s.col__taxon_id)`,
+	},
+	{MissingTaxon, "vernacular", `
+SELECT v.col__name, v.col__taxon_id FROM vernacular v
+  WHERE NOT EXISTS (SELECT 1 FROM taxon t WHERE t.col__id = v.col__taxon_id)`,
+	},
+	{MissingTaxon, "distribution", `
+SELECT d.col__area, d.col__taxon_id FROM distribution d
+  WHERE NOT EXISTS (SELECT 1 FROM taxon t WHERE t.col__id = d.col__taxon_id)`,
+	},
+	{MissingName, "taxon", `
+SELECT t.col__id, t.col__name_id FROM taxon t
+  WHERE NOT EXISTS (SELECT 1 FROM name n WHERE n.col__id = t.col__name_id)`,
+	},
+	{MissingName, "synonym", `
+SELECT s.col__id, s.col__name_id FROM synonym s
+  WHERE NOT EXISTS (SELECT 1 FROM name n WHERE n.col__id = s.col__name_id)`,
+	},
+	{MissingName, "type_material", `
+SELECT tm.col__id, tm.col__name_id FROM type_material tm
+  WHERE NOT EXISTS (SELECT 1 FROM name n WHERE n.col__id = tm.col__name_id)`,
+	},
+	{MissingName, "name_relation", `
+SELECT r.col__name_id, r.col__related_name_id FROM name_relation r
+  WHERE NOT EXISTS (SELECT 1 FROM name n WHERE n.col__id = r.col__related_name_id)
+UNION ALL
+SELECT r.col__related_name_id, r.col__name_id FROM name_relation r
+  WHERE NOT EXISTS (SELECT 1 FROM name n WHERE n.col__id = r.col__name_id)`,
+	},
+	{DuplicateID, "synonym", `
+SELECT col__id, '' FROM synonym
+  WHERE col__id != ''
+  GROUP BY col__id HAVING count(*) > 1`,
+	},
+	{DuplicateID, "taxon, synonym", `
+SELECT DISTINCT s.col__id, '' FROM synonym s
+  JOIN taxon t ON t.col__id = s.col__id`,
+	},
+}
+
+func (c queryCheck) run(ctx context.Context, db *sql.DB, r *Report) error {
+	rows, err := db.QueryContext(ctx, c.query)
+	if err != nil {
+		return fmt.Errorf("%s check of %s: %w", c.category, c.table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		iss := Issue{Category: c.category, Table: c.table}
+		if err = rows.Scan(&iss.ID, &iss.Ref); err != nil {
+			return err
+		}
+		r.add(iss)
+	}
+	return rows.Err()
+}
+
+// refColumns are columns that refer to references. Some of them contain
+// several IDs separated by comma.
+var refColumns = []struct {
+	table, id, ref string
+}{
+	{"name", "col__id", "col__reference_id"},
+	{"taxon", "col__id", "col__according_to_id"},
+	{"taxon", "col__id", "col__reference_id"},
+	{"synonym", "col__id", "col__according_to_id"},
+	{"synonym", "col__id", "col__reference_id"},
+	{"vernacular", "col__taxon_id", "col__reference_id"},
+	{"distribution", "col__taxon_id", "col__reference_id"},
+	{"type_material", "col__id", "col__reference_id"},
+	{"name_relation", "col__name_id", "col__reference_id"},
+}
+
+func checkReferences(ctx context.Context, db *sql.DB, r *Report) error {
+	refs, err := loadIDs(ctx, db, "SELECT col__id FROM reference")
+	if err != nil {
+		return err
+	}
+
+	for _, v := range refColumns {
+		q := fmt.Sprintf(
+			"SELECT %s, %s FROM %s WHERE %s != ''",
+			v.id, v.ref, v.table, v.ref,
+		)
+		rows, err := db.QueryContext(ctx, q)
+		if err != nil {
+			return err
+		}
+
+		for rows.Next() {
+			var id, ref string
+			if err = rows.Scan(&id, &ref); err != nil {
+				rows.Close()
+				return err
+			}
+			for _, refID := range strings.Split(ref, ",") {
+				refID = strings.TrimSpace(refID)
+				if _, ok := refs[refID]; ok || refID == "" {
+					continue
+				}
+				r.add(Issue{
+					Category: MissingReference, Table: v.table, ID: id, Ref: refID,
+				})
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkCycles finds taxa that are their own ancestors. Every cycle is
+// reported once. A taxon that is its own parent is a root, some sources,
+// like NCBI, write top-level taxa this way.
+func checkCycles(ctx context.Context, db *sql.DB, r *Report) error {
+	q := `
+SELECT col__id, col__parent_id FROM taxon
+  WHERE col__parent_id != '' AND col__parent_id != col__id`
+	rows, err := db.QueryContext(ctx, q)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	parents := make(map[string]string)
+	for rows.Next() {
+		var id, parentID string
+		if err = rows.Scan(&id, &parentID); err != nil {
+			return err
+		}
+		parents[id] = parentID
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	const (
+		inPath = iota + 1
+		done
+	)
+	state := make(map[string]int)
+	for id := range parents {
+		if state[id] == done {
+			continue
+		}
+		var path []string
+		cur := id
+		for {
+			if state[cur] == done {
+				break
+			}
+			if state[cur] == inPath {
+				r.add(Issue{
+					Category: HierarchyCycle,
+					Table:    "taxon",
+					ID:       cur,
+					Ref:      cycle(path, cur),
+				})
+				break
+			}
+			state[cur] = inPath
+			path = append(path, cur)
+			next, ok := parents[cur]
+			if !ok {
+				break
+			}
+			cur = next
+		}
+		for _, v := range path {
+			state[v] = done
+		}
+	}
+	return nil
+}
+
+// cycle returns IDs of ancestors in a cycle that starts at id.
+func cycle(path []string, id string) string {
+	for i, v := range path {
+		if v == id {
+			return strings.Join(append(path[i+1:], id), " -> ")
+		}
+	}
+	return id
+}
+
+func loadIDs(
+	ctx context.Context,
+	db dbtx,
+	q string,
+	args ...any,
+) (map[string]struct{}, error) {
+	rows, err := db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make(map[string]struct{})
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		res[id] = struct{}{}
+	}
+	return res, rows.Err()
+}
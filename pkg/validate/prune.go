@@ -0,0 +1,108 @@
+package validate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// pruneSteps are run in order, because removal of taxa leaves synonyms,
+// children and other records without their taxa.
+var pruneSteps = []struct {
+	table string
+	query string
+}{
+	{"taxon", `
+DELETE FROM taxon
+  WHERE NOT EXISTS (SELECT 1 FROM name n WHERE n.col__id = taxon.col__name_id)`,
+	},
+	{"synonym", `
+DELETE FROM synonym
+  WHERE NOT EXISTS (SELECT 1 FROM name n WHERE n.col__id = synonym.col__name_id)
+     OR NOT EXISTS (SELECT 1 FROM taxon t WHERE t.col__id = synonym.col__taxon_id)`,
+	},
+	{"taxon", `
+UPDATE taxon SET col__parent_id = ''
+  WHERE col__parent_id != ''
+    AND NOT EXISTS (SELECT 1 FROM taxon p WHERE p.col__id = taxon.col__parent_id)`,
+	},
+	{"type_material", `
+DELETE FROM type_material
+  WHERE NOT EXISTS (
+    SELECT 1 FROM name n WHERE n.col__id = type_material.col__name_id)`,
+	},
+	{"name_relation", `
+DELETE FROM name_relation
+  WHERE NOT EXISTS (
+    SELECT 1 FROM name n WHERE n.col__id = name_relation.col__name_id)
+     OR NOT EXISTS (
+    SELECT 1 FROM name n WHERE n.col__id = name_relation.col__related_name_id)`,
+	},
+}
+
+// taxonTables contain records that are removed with their taxa.
+var taxonTables = []string{
+	"vernacular", "distribution", "media", "treatment", "species_estimate",
+	"taxon_property", "species_interaction", "taxon_concept_relation",
+}
+
+// Prune makes an archive that contains only a part of a data source
+// consistent. Taxa and synonyms without names, and synonyms without
+// accepted taxa are removed, parents that are not in the archive are
+// detached, other records that refer to missing names or taxa are removed.
+// It returns the number of changed records per table.
+func Prune(ctx context.Context, db *sql.DB) (map[string]int, error) {
+	res := make(map[string]int)
+	if err := prune(ctx, db, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// dbtx is a database or a transaction.
+type dbtx interface {
+	ExecContext(context.Context, string, ...any) (sql.Result, error)
+	QueryContext(context.Context, string, ...any) (*sql.Rows, error)
+}
+
+func prune(ctx context.Context, db dbtx, res map[string]int) error {
+	for _, v := range pruneSteps {
+		if err := execCount(ctx, db, res, v.table, v.query); err != nil {
+			return err
+		}
+	}
+
+	for _, v := range taxonTables {
+		q := fmt.Sprintf(`
+DELETE FROM %[1]s
+  WHERE NOT EXISTS (SELECT 1 FROM taxon t WHERE t.col__id = %[1]s.col__taxon_id)`,
+			v,
+		)
+		if err := execCount(ctx, db, res, v, q); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// execCount runs a query and adds the number of changed records to res.
+func execCount(
+	ctx context.Context,
+	db dbtx,
+	res map[string]int,
+	table, q string,
+	args ...any,
+) error {
+	r, err := db.ExecContext(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("pruning %s: %w", table, err)
+	}
+	num, err := r.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if num > 0 {
+		res[table] += int(num)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package validate_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sfborg/harvester/pkg/validate"
+	"github.com/sfborg/sflib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrune(t *testing.T) {
+	assert := assert.New(t)
+	arc := sflib.NewSfga()
+	err := arc.Create(t.TempDir())
+	assert.Nil(err)
+	db, err := arc.Connect()
+	assert.Nil(err)
+	defer arc.Close()
+
+	stmts := []string{
+		`INSERT INTO name (col__id, gn__scientific_name_string, col__scientific_name)
+		  VALUES ('n2', 'B', 'B'), ('n3', 'C', 'C'), ('n4', 'D', 'D')`,
+		`INSERT INTO taxon (col__id, col__name_id, col__parent_id) VALUES
+		  ('t1', 'n1', ''), ('t2', 'n2', 't1'), ('t3', 'n3', 't2')`,
+		`INSERT INTO synonym (col__id, col__taxon_id, col__name_id) VALUES
+		  ('s1', 't1', 'n4'), ('s2', 't3', 'n4'), ('s3', 't3', 'n9')`,
+		`INSERT INTO vernacular (col__taxon_id, col__name) VALUES
+		  ('t1', 'foo'), ('t3', 'bar')`,
+		`INSERT INTO name_relation (col__name_id, col__related_name_id)
+		  VALUES ('n1', 'n2'), ('n4', 'n3')`,
+	}
+	for _, v := range stmts {
+		_, err = db.Exec(v)
+		assert.Nil(err, v)
+	}
+
+	res, err := validate.Prune(context.Background(), db)
+	assert.Nil(err)
+	assert.Equal(map[string]int{
+		"taxon": 2, "synonym": 2, "vernacular": 1, "name_relation": 1,
+	}, res)
+
+	var parent string
+	err = db.QueryRow(
+		"SELECT col__parent_id FROM taxon WHERE col__id = 't2'",
+	).Scan(&parent)
+	assert.Nil(err)
+	assert.Empty(parent)
+
+	rep, err := validate.Check(context.Background(), db)
+	assert.Nil(err)
+	assert.True(rep.OK())
+}
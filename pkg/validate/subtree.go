@@ -0,0 +1,230 @@
+package validate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrRootNotFound is returned by Subtree when the root taxon is not in the
+// archive.
+var ErrRootNotFound = errors.New("root taxon not found")
+
+// usedRefColumns are all columns that refer to references. Some of them
+// contain several IDs separated by comma.
+var usedRefColumns = []struct {
+	table, column string
+}{
+	{"author", "col__reference_id"},
+	{"name", "col__reference_id"},
+	{"taxon", "col__according_to_id"},
+	{"taxon", "col__reference_id"},
+	{"synonym", "col__according_to_id"},
+	{"synonym", "col__reference_id"},
+	{"vernacular", "col__reference_id"},
+	{"name_relation", "col__reference_id"},
+	{"type_material", "col__reference_id"},
+	{"distribution", "col__reference_id"},
+	{"species_estimate", "col__reference_id"},
+	{"taxon_property", "col__reference_id"},
+	{"species_interaction", "col__reference_id"},
+	{"taxon_concept_relation", "col__reference_id"},
+}
+
+// Subtree keeps only the taxon given by its ID or scientific name, its
+// descendants, their synonyms and records that belong to them (names,
+// vernaculars, distributions, references and such). Everything else is
+// removed from the archive. It returns the number of changed records per
+// table.
+func Subtree(
+	ctx context.Context,
+	db *sql.DB,
+	root string,
+) (map[string]int, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rootID, err := findRoot(ctx, tx, root)
+	if err != nil {
+		return nil, err
+	}
+
+	keep, err := descendants(ctx, tx, rootID)
+	if err != nil {
+		return nil, err
+	}
+
+	q := "CREATE TEMP TABLE subtree (id TEXT PRIMARY KEY)"
+	if _, err = tx.ExecContext(ctx, q); err != nil {
+		return nil, err
+	}
+	if err = insertIDs(ctx, tx, keep); err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]int)
+	q = "DELETE FROM taxon WHERE col__id NOT IN (SELECT id FROM subtree)"
+	if err = execCount(ctx, tx, res, "taxon", q); err != nil {
+		return nil, err
+	}
+	// parents outside of the subtree are detached by prune.
+	if err = prune(ctx, tx, res); err != nil {
+		return nil, err
+	}
+
+	q = `
+DELETE FROM name
+  WHERE NOT EXISTS (SELECT 1 FROM taxon t WHERE t.col__name_id = name.col__id)
+    AND NOT EXISTS (SELECT 1 FROM synonym s WHERE s.col__name_id = name.col__id)`
+	if err = execCount(ctx, tx, res, "name", q); err != nil {
+		return nil, err
+	}
+	if err = prune(ctx, tx, res); err != nil {
+		return nil, err
+	}
+	if err = pruneReferences(ctx, tx, res); err != nil {
+		return nil, err
+	}
+
+	if _, err = tx.ExecContext(ctx, "DROP TABLE subtree"); err != nil {
+		return nil, err
+	}
+	return res, tx.Commit()
+}
+
+// findRoot returns the ID of the root taxon. The root is either an ID of
+// a taxon, or a scientific name of exactly one taxon, with or without
+// authorship.
+func findRoot(ctx context.Context, tx *sql.Tx, root string) (string, error) {
+	var id string
+	err := tx.QueryRowContext(ctx,
+		"SELECT col__id FROM taxon WHERE col__id = ?", root,
+	).Scan(&id)
+	if err == nil {
+		return id, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+
+	q := `
+SELECT t.col__id FROM taxon t
+  JOIN name n ON n.col__id = t.col__name_id
+  WHERE n.col__scientific_name = ?1 OR n.gn__scientific_name_string = ?1`
+	ids, err := loadIDs(ctx, tx, q, root)
+	if err != nil {
+		return "", err
+	}
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("%w: '%s'", ErrRootNotFound, root)
+	case 1:
+		for k := range ids {
+			id = k
+		}
+		return id, nil
+	default:
+		return "", fmt.Errorf(
+			"root '%s' matches %d taxa, use ID of the taxon instead",
+			root, len(ids),
+		)
+	}
+}
+
+// descendants returns IDs of the root taxon and all its descendants.
+func descendants(
+	ctx context.Context,
+	tx *sql.Tx,
+	rootID string,
+) ([]string, error) {
+	q := "SELECT col__id, col__parent_id FROM taxon WHERE col__parent_id != ''"
+	rows, err := tx.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	children := make(map[string][]string)
+	for rows.Next() {
+		var id, parentID string
+		if err = rows.Scan(&id, &parentID); err != nil {
+			return nil, err
+		}
+		children[parentID] = append(children[parentID], id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// seen protects from cycles in the hierarchy.
+	seen := map[string]struct{}{rootID: {}}
+	res := []string{rootID}
+	for i := 0; i < len(res); i++ {
+		for _, v := range children[res[i]] {
+			if _, ok := seen[v]; ok {
+				continue
+			}
+			seen[v] = struct{}{}
+			res = append(res, v)
+		}
+	}
+	return res, nil
+}
+
+func insertIDs(ctx context.Context, tx *sql.Tx, ids []string) error {
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO subtree (id) VALUES (?)")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, v := range ids {
+		if _, err = stmt.ExecContext(ctx, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneReferences removes references that are not used by any record.
+func pruneReferences(
+	ctx context.Context,
+	tx *sql.Tx,
+	res map[string]int,
+) error {
+	used := make(map[string]struct{})
+	for _, v := range usedRefColumns {
+		q := fmt.Sprintf(
+			"SELECT %[1]s FROM %[2]s WHERE %[1]s != ''", v.column, v.table,
+		)
+		ids, err := loadIDs(ctx, tx, q)
+		if err != nil {
+			return err
+		}
+		for k := range ids {
+			for _, id := range strings.Split(k, ",") {
+				used[strings.TrimSpace(id)] = struct{}{}
+			}
+		}
+	}
+
+	refs, err := loadIDs(ctx, tx, "SELECT col__id FROM reference")
+	if err != nil {
+		return err
+	}
+	for k := range refs {
+		if _, ok := used[k]; ok {
+			continue
+		}
+		q := "DELETE FROM reference WHERE col__id = ?"
+		if err = execCount(ctx, tx, res, "reference", q, k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
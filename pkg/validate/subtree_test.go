@@ -0,0 +1,103 @@
+package validate_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/sfborg/harvester/pkg/validate"
+	"github.com/sfborg/sflib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubtree(t *testing.T) {
+	assert := assert.New(t)
+	tests := []struct {
+		msg   string
+		root  string
+		taxa  []string
+		names []string
+		refs  []string
+		err   string
+	}{
+		{"name", "Aves", []string{"t2", "t3"}, []string{"n2", "n3", "n6"},
+			[]string{"r1", "r3"}, ""},
+		{"name string", "Felis Linnaeus, 1758", []string{"t5"},
+			[]string{"n5", "n7"}, []string{"r2"}, ""},
+		{"id", "t4", []string{"t4", "t5", "t6"},
+			[]string{"n4", "n5", "n7", "n8"}, []string{"r2"}, ""},
+		{"missing", "Plantae", nil, nil, nil, "root taxon not found"},
+		{"ambiguous", "Bubo", nil, nil, nil, "matches 2 taxa"},
+	}
+
+	for _, v := range tests {
+		db := subtreeDB(t)
+		_, err := validate.Subtree(context.Background(), db, v.root)
+		if v.err != "" {
+			assert.ErrorContains(err, v.err, v.msg)
+			continue
+		}
+		assert.Nil(err, v.msg)
+		assert.Equal(v.taxa, ids(t, db, "SELECT col__id FROM taxon"), v.msg)
+		assert.Equal(v.names, ids(t, db, "SELECT col__id FROM name"), v.msg)
+		assert.Equal(v.refs, ids(t, db, "SELECT col__id FROM reference"), v.msg)
+
+		q := "SELECT count(*) FROM taxon WHERE col__parent_id != ''"
+		var num int
+		assert.Nil(db.QueryRow(q).Scan(&num), v.msg)
+		assert.Equal(len(v.taxa)-1, num, v.msg)
+
+		rep, err := validate.Check(context.Background(), db)
+		assert.Nil(err, v.msg)
+		assert.True(rep.OK(), v.msg)
+	}
+}
+
+func subtreeDB(t *testing.T) *sql.DB {
+	arc := sflib.NewSfga()
+	err := arc.Create(t.TempDir())
+	assert.Nil(t, err)
+	db, err := arc.Connect()
+	assert.Nil(t, err)
+	t.Cleanup(func() { arc.Close() })
+
+	stmts := []string{
+		`INSERT INTO reference (col__id, col__citation) VALUES
+		  ('r1', 'Owls'), ('r2', 'Cats'), ('r3', 'Eagle owls')`,
+		`INSERT INTO name (col__id, gn__scientific_name_string,
+		    col__scientific_name, col__reference_id) VALUES
+		  ('n1', 'Animalia', 'Animalia', ''), ('n2', 'Aves', 'Aves', ''),
+		  ('n3', 'Bubo', 'Bubo', 'r3'), ('n4', 'Mammalia', 'Mammalia', ''),
+		  ('n5', 'Felis Linnaeus, 1758', 'Felis', ''),
+		  ('n6', 'Strix bubo', 'Strix bubo', ''),
+		  ('n7', 'Catus', 'Catus', ''), ('n8', 'Bubo', 'Bubo', '')`,
+		`INSERT INTO taxon (col__id, col__name_id, col__parent_id,
+		    col__reference_id) VALUES
+		  ('t1', 'n1', '', ''), ('t2', 'n2', 't1', ''), ('t3', 'n3', 't2', 'r1'),
+		  ('t4', 'n4', 't1', ''), ('t5', 'n5', 't4', 'r2'),
+		  ('t6', 'n8', 't4', '')`,
+		`INSERT INTO synonym (col__id, col__taxon_id, col__name_id) VALUES
+		  ('s1', 't3', 'n6'), ('s2', 't5', 'n7')`,
+		`INSERT INTO vernacular (col__taxon_id, col__name) VALUES
+		  ('t3', 'eagle owl'), ('t5', 'cat')`,
+	}
+	for _, v := range stmts {
+		_, err = db.Exec(v)
+		assert.Nil(t, err, v)
+	}
+	return db
+}
+
+func ids(t *testing.T, db *sql.DB, q string) []string {
+	rows, err := db.Query(q + " ORDER BY col__id")
+	assert.Nil(t, err)
+	defer rows.Close()
+
+	var res []string
+	for rows.Next() {
+		var id string
+		assert.Nil(t, rows.Scan(&id))
+		res = append(res, id)
+	}
+	return res
+}
@@ -0,0 +1,136 @@
+// Package validate checks referential integrity of SFGA archives and keeps
+// it in archives that contain only a part of a data source.
+package validate
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"slices"
+)
+
+// Category is a kind of integrity problem.
+type Category string
+
+const (
+	// DanglingParent is a taxon with a parent that does not exist.
+	DanglingParent Category = "dangling parent"
+
+	// ParentIsSynonym is a taxon with a parent that is a synonym.
+	ParentIsSynonym Category = "parent is synonym"
+
+	// SynonymOfSynonym is a synonym that points to another synonym.
+	SynonymOfSynonym Category = "synonym of synonym"
+
+	// HierarchyCycle is a taxon that is its own ancestor.
+	HierarchyCycle Category = "hierarchy cycle"
+
+	// MissingTaxon is a record that refers to a taxon that does not exist.
+	MissingTaxon Category = "missing taxon"
+
+	// MissingName is a record that refers to a name that does not exist.
+	MissingName Category = "missing name"
+
+	// MissingReference is a record that refers to a reference that does not
+	// exist.
+	MissingReference Category = "missing reference"
+
+	// DuplicateID is an ID that is used by more than one name usage.
+	DuplicateID Category = "duplicate ID"
+)
+
+// examplesNum is the maximum number of examples kept for a category.
+const examplesNum = 5
+
+// Issue is an integrity problem of a record.
+type Issue struct {
+	Category Category `json:"category"`
+
+	// Table where the problematic record is located.
+	Table string `json:"table"`
+
+	// ID of the record, or its closest identifier.
+	ID string `json:"id"`
+
+	// Ref is the value that cannot be resolved.
+	Ref string `json:"ref,omitempty"`
+}
+
+// Report contains results of a validation.
+type Report struct {
+	// Counts contain the number of issues per category.
+	Counts map[Category]int `json:"counts"`
+
+	// Examples contain a few issues per category.
+	Examples map[Category][]Issue `json:"examples"`
+}
+
+func newReport() *Report {
+	return &Report{
+		Counts:   make(map[Category]int),
+		Examples: make(map[Category][]Issue),
+	}
+}
+
+func (r *Report) add(iss Issue) {
+	r.Counts[iss.Category]++
+	if len(r.Examples[iss.Category]) < examplesNum {
+		r.Examples[iss.Category] = append(r.Examples[iss.Category], iss)
+	}
+}
+
+// OK returns true if no issues were found.
+func (r *Report) OK() bool {
+	return r.Total() == 0
+}
+
+// Total returns the number of found issues.
+func (r *Report) Total() int {
+	var res int
+	for _, v := range r.Counts {
+		res += v
+	}
+	return res
+}
+
+// Categories returns sorted categories that have issues.
+func (r *Report) Categories() []Category {
+	var res []Category
+	for k := range r.Counts {
+		res = append(res, k)
+	}
+	slices.Sort(res)
+	return res
+}
+
+// Check runs all integrity checks on the archive database.
+func Check(ctx context.Context, db *sql.DB) (*Report, error) {
+	res := newReport()
+
+	for _, v := range queryChecks {
+		if err := v.run(ctx, db, res); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := checkReferences(ctx, db, res); err != nil {
+		return nil, err
+	}
+
+	if err := checkCycles(ctx, db, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Log writes found issues to the log.
+func (r *Report) Log() {
+	if r.OK() {
+		slog.Info("no integrity issues found")
+		return
+	}
+	for _, v := range r.Categories() {
+		slog.Warn("integrity issues", "category", v, "count", r.Counts[v])
+	}
+}
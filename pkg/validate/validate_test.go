@@ -0,0 +1,89 @@
+package validate_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sfborg/harvester/pkg/validate"
+	"github.com/sfborg/sflib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	assert := assert.New(t)
+	arc := sflib.NewSfga()
+	err := arc.Create(t.TempDir())
+	assert.Nil(err)
+	db, err := arc.Connect()
+	assert.Nil(err)
+	defer arc.Close()
+
+	stmts := []string{
+		`INSERT INTO name (col__id, gn__scientific_name_string, col__scientific_name)
+		  VALUES ('n1', 'A', 'A'), ('n2', 'B', 'B'), ('n3', 'C', 'C'),
+		  ('n4', 'D', 'D'), ('n5', 'E', 'E'), ('n6', 'F', 'F')`,
+		`INSERT INTO taxon (col__id, col__name_id, col__parent_id) VALUES
+		  ('t1', 'n1', ''), ('t2', 'n2', 't1'), ('t3', 'n3', 'nope'),
+		  ('t4', 'n4', 't5'), ('t5', 'n5', 't4'), ('t6', 'missing', 's1')`,
+		`INSERT INTO synonym (col__id, col__taxon_id, col__name_id) VALUES
+		  ('s1', 't1', 'n6'), ('s2', 's1', 'n6'), ('t2', 't1', 'n6')`,
+		`INSERT INTO vernacular (col__taxon_id, col__name) VALUES ('t9', 'foo')`,
+		`INSERT INTO distribution (col__taxon_id, col__area, col__reference_id)
+		  VALUES ('t1', 'Europe', 'r1')`,
+	}
+	for _, v := range stmts {
+		_, err = db.Exec(v)
+		assert.Nil(err, v)
+	}
+
+	res, err := validate.Check(context.Background(), db)
+	assert.Nil(err)
+	assert.False(res.OK())
+
+	tests := []struct {
+		msg   string
+		cat   validate.Category
+		count int
+	}{
+		{"dangling", validate.DanglingParent, 1},
+		{"parent synonym", validate.ParentIsSynonym, 1},
+		{"syn of syn", validate.SynonymOfSynonym, 1},
+		{"cycle", validate.HierarchyCycle, 1},
+		{"missing taxon", validate.MissingTaxon, 1},
+		{"missing name", validate.MissingName, 1},
+		{"missing ref", validate.MissingReference, 1},
+		{"duplicate", validate.DuplicateID, 1},
+	}
+	for _, v := range tests {
+		assert.Equal(v.count, res.Counts[v.cat], v.msg)
+	}
+	assert.Equal(8, res.Total())
+	assert.Equal("t3", res.Examples[validate.DanglingParent][0].ID)
+}
+
+func TestCheckSelfParentRoot(t *testing.T) {
+	assert := assert.New(t)
+	arc := sflib.NewSfga()
+	err := arc.Create(t.TempDir())
+	assert.Nil(err)
+	db, err := arc.Connect()
+	assert.Nil(err)
+	defer arc.Close()
+
+	// NCBI writes top-level taxa as their own parents.
+	stmts := []string{
+		`INSERT INTO name (col__id, gn__scientific_name_string, col__scientific_name)
+		  VALUES ('n1', 'A', 'A'), ('n2', 'B', 'B')`,
+		`INSERT INTO taxon (col__id, col__name_id, col__parent_id) VALUES
+		  ('t1', 'n1', 't1'), ('t2', 'n2', 't1')`,
+	}
+	for _, v := range stmts {
+		_, err = db.Exec(v)
+		assert.Nil(err, v)
+	}
+
+	res, err := validate.Check(context.Background(), db)
+	assert.Nil(err)
+	assert.True(res.OK())
+	assert.Equal(0, res.Counts[validate.HierarchyCycle])
+}
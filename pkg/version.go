@@ -11,3 +11,9 @@ var Build = "n/a"
 func GetVersion() gnvers.Version {
 	return gnvers.Version{Version: Version, Build: Build}
 }
+
+// UserAgent returns the User-Agent header used in HTTP requests.
+func UserAgent() string {
+	return "sfborg-harvester/" + Version +
+		" (+https://github.com/sfborg/harvester)"
+}